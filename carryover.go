@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Values for daily_carryover
+const (
+	carryoverOff  = "off"
+	carryoverCopy = "copy"
+	carryoverMove = "move"
+)
+
+// dailyCarryover is what a new daily note takes from the previous one
+type dailyCarryover struct {
+	Source string     // previous daily note
+	Tasks  []noteTask // its unfinished tasks
+	Lines  []string   // their source lines, dedented
+}
+
+// previousDailyNote returns the most recent daily note dated before today
+// (YYYY-MM-DD)
+func previousDailyNote(idx *NoteIndex, daily notePeriod, today string) (*NoteEntry, bool) {
+	var latestPath, latestDate string
+	for date, path := range periodicNoteDates(idx, daily) {
+		if date < today && date > latestDate {
+			latestPath, latestDate = path, date
+		}
+	}
+	if latestPath == "" {
+		return nil, false
+	}
+	return idx.Get(latestPath)
+}
+
+// collectCarryover finds the unfinished tasks of the latest daily note
+// before today. It returns false when there is nothing to carry over.
+func collectCarryover(idx *NoteIndex, daily notePeriod, today string) (dailyCarryover, bool) {
+	entry, ok := previousDailyNote(idx, daily, today)
+	if !ok {
+		return dailyCarryover{}, false
+	}
+
+	content, err := os.ReadFile(entry.Path)
+	if err != nil {
+		return dailyCarryover{}, false
+	}
+	carry := dailyCarryover{Source: entry.Path}
+	lines := strings.Split(string(content), "\n")
+	for _, task := range entry.Tasks {
+		if task.Done || task.Line > len(lines) {
+			continue
+		}
+		carry.Tasks = append(carry.Tasks, task)
+		carry.Lines = append(carry.Lines, strings.TrimRight(lines[task.Line-1], " \t\r"))
+	}
+	if len(carry.Tasks) == 0 {
+		return dailyCarryover{}, false
+	}
+
+	// Keep nesting between the tasks but start at the left margin
+	indent := -1
+	for _, line := range carry.Lines {
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, line := range carry.Lines {
+		carry.Lines[i] = line[indent:]
+	}
+	return carry, true
+}
+
+// section renders the carried tasks for a new daily note at path, followed
+// by a link back to the note they came from
+func (c dailyCarryover) section(idx *NoteIndex, path string) string {
+	var b strings.Builder
+	for _, line := range c.Lines {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\nCarried over from " + noteReference(idx, path, c.Source) + "\n\n")
+	return b.String()
+}
+
+// noteReference links from one note to another: a Denote link when the
+// target has an identifier, otherwise a relative markdown link
+func noteReference(idx *NoteIndex, from, to string) string {
+	name := strings.TrimSuffix(filepath.Base(to), filepath.Ext(to))
+	if entry, ok := idx.Get(to); ok {
+		if entry.Identifier != "" {
+			return fmt.Sprintf("[[denote:%s]]", entry.Identifier)
+		}
+		if entry.Title != "" {
+			name = entry.Title
+		}
+	}
+	rel, err := filepath.Rel(filepath.Dir(from), to)
+	if err != nil {
+		rel = to
+	}
+	return fmt.Sprintf("[%s](%s)", name, strings.ReplaceAll(filepath.ToSlash(rel), " ", "%20"))
+}
+
+// removeCarriedTasks deletes carried task lines from their source note,
+// leaving any line that has changed since it was read
+func removeCarriedTasks(idx *NoteIndex, carry dailyCarryover) error {
+	info, err := os.Stat(carry.Source)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(carry.Source)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	drop := make(map[int]bool, len(carry.Tasks))
+	for _, task := range carry.Tasks {
+		if task.Line > len(lines) {
+			continue
+		}
+		match := taskLinePattern.FindStringSubmatch(strings.TrimSuffix(lines[task.Line-1], "\r"))
+		if match != nil && match[2] == " " && strings.TrimSpace(match[3]) == task.Text {
+			drop[task.Line-1] = true
+		}
+	}
+
+	kept := lines[:0]
+	for i, line := range lines {
+		if !drop[i] {
+			kept = append(kept, line)
+		}
+	}
+	if err := os.WriteFile(carry.Source, []byte(strings.Join(kept, "\n")), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return idx.Update(carry.Source)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// parseDailyDate reads a day given on the command line: YYYY-MM-DD, or
+// today, yesterday or tomorrow
+func parseDailyDate(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(value), now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, today, yesterday or tomorrow)", value)
+	}
+	return day, nil
+}
+
+// openDailyNote opens the daily note for a day, creating it if needed
+func (m *model) openDailyNote(day time.Time) tea.Cmd {
+	daily, _ := findPeriod(m.config, "daily")
+	return m.openPeriodicNote(daily, day)
+}
+
+// openCalendar shows the calendar on today's month
+func (m *model) openCalendar() {
+	now := time.Now()
+	m.calendarMode = true
+	m.calendarDay = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// moveCalendar moves the calendar cursor by days and months, keeping the
+// day of the month where it can when changing month
+func (m *model) moveCalendar(days, months int) {
+	day := m.calendarDay.AddDate(0, 0, days)
+	if months != 0 {
+		first := time.Date(day.Year(), day.Month()+time.Month(months), 1, 0, 0, 0, 0, day.Location())
+		last := first.AddDate(0, 1, -1).Day()
+		day = first.AddDate(0, 0, min(day.Day(), last)-1)
+	}
+	m.calendarDay = day
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pdxmph/notes-tui/internal/denote"
+	"github.com/pdxmph/notes-tui/internal/frontmatter"
+)
+
+// denoteDrift describes where a Denote note's filename and frontmatter
+// disagree about its title, tags or identifier
+type denoteDrift struct {
+	Path       string
+	Name       denote.Name        // the parsed filename
+	Order      []denote.Component // component order for a new filename
+	Identifier string             // from the filename, which always wins
+	FileSlug   string             // title part of the filename
+	FileTags   []string           // keywords in the filename
+	MetaTitle  string
+	MetaTags   []string
+	MetaID     string // identifier in frontmatter, if any
+
+	TitleDrift bool
+	TagsDrift  bool
+	IDDrift    bool
+}
+
+// Drifted reports whether anything disagrees
+func (d *denoteDrift) Drifted() bool {
+	return d.TitleDrift || d.TagsDrift || d.IDDrift
+}
+
+// checkDenoteSync compares a Denote note's filename with its frontmatter.
+// Notes that aren't Denote-named or have no frontmatter give nil.
+func checkDenoteSync(path string, order []denote.Component) (*denoteDrift, error) {
+	name, ok := denote.Parse(filepath.Base(path))
+	if !ok {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := frontmatter.Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	if doc.Meta == nil {
+		return nil, nil
+	}
+
+	drift := &denoteDrift{
+		Path:       path,
+		Name:       name,
+		Order:      order,
+		Identifier: name.Identifier,
+		FileSlug:   name.Title,
+		FileTags:   name.Keywords,
+		MetaTitle:  doc.Meta.Get("title"),
+		MetaTags:   doc.Meta.Strings("tags"),
+		MetaID:     doc.Meta.Get("identifier"),
+	}
+	drift.TitleDrift = drift.MetaTitle != "" && denote.SlugTitle(drift.MetaTitle) != name.Title
+	drift.TagsDrift = !sameKeywords(denote.KeywordsFor(drift.MetaTags), name.Keywords)
+	drift.IDDrift = drift.MetaID != "" && drift.MetaID != name.Identifier
+	return drift, nil
+}
+
+// sameKeywords compares two keyword lists, ignoring order
+func sameKeywords(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// frontmatterName is the filename the note gets when its frontmatter wins.
+// The signature and extension are kept.
+func (d *denoteDrift) frontmatterName() string {
+	name := d.Name
+	if d.TitleDrift {
+		name.Title = denote.SlugTitle(d.MetaTitle)
+	}
+	name.Keywords = denote.KeywordsFor(d.MetaTags)
+	return name.Format(d.Order)
+}
+
+// Diff describes the disagreement and what each choice would change
+func (d *denoteDrift) Diff() []string {
+	var lines []string
+	if d.TitleDrift {
+		lines = append(lines, fmt.Sprintf("title:      filename %q, frontmatter %q", d.FileSlug, d.MetaTitle))
+	}
+	if d.TagsDrift {
+		lines = append(lines, fmt.Sprintf("tags:       filename [%s], frontmatter [%s]", strings.Join(d.FileTags, ", "), strings.Join(d.MetaTags, ", ")))
+	}
+	if d.IDDrift {
+		lines = append(lines, fmt.Sprintf("identifier: filename %s, frontmatter %s", d.Identifier, d.MetaID))
+	}
+
+	lines = append(lines, "", "Frontmatter wins:")
+	if name := d.frontmatterName(); name != filepath.Base(d.Path) {
+		lines = append(lines, "  rename to "+name)
+	}
+	if d.IDDrift {
+		lines = append(lines, "  identifier: "+d.Identifier)
+	}
+
+	lines = append(lines, "", "Filename wins:")
+	if d.TitleDrift {
+		lines = append(lines, fmt.Sprintf("  title: %s", d.Name.ReadableTitle()))
+	}
+	if d.TagsDrift {
+		lines = append(lines, fmt.Sprintf("  tags: [%s]", strings.Join(d.FileTags, ", ")))
+	}
+	if d.IDDrift {
+		lines = append(lines, "  identifier: "+d.Identifier)
+	}
+	return lines
+}
+
+// syncNote resolves a drifted note in favour of its frontmatter or its
+// filename and returns where the note is afterwards. The edit and any
+// rename are recorded in op, which should be committed even on an error,
+// since the frontmatter identifier may already have been rewritten.
+func syncNote(idx *NoteIndex, op *operation, d *denoteDrift, frontmatterWins bool) (string, error) {
+	if err := op.edit(d.Path); err != nil {
+		return "", err
+	}
+	if !frontmatterWins {
+		return d.Path, syncFromFilename(idx, d)
+	}
+	newPath, err := syncFromFrontmatter(idx, d)
+	if err != nil {
+		return "", err
+	}
+	op.renamed(d.Path, newPath)
+	return newPath, nil
+}
+
+// syncFromFrontmatter renames the note to match its frontmatter, keeping
+// its identifier, and returns the new path
+func syncFromFrontmatter(idx *NoteIndex, d *denoteDrift) (string, error) {
+	if d.IDDrift {
+		if err := writeSyncedFrontmatter(idx, d.Path, func(meta *frontmatter.Frontmatter) error {
+			return meta.Set("identifier", d.Identifier)
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	newPath := filepath.Join(filepath.Dir(d.Path), d.frontmatterName())
+	if newPath == d.Path {
+		return d.Path, nil
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		return "", fmt.Errorf("%s already exists", filepath.Base(newPath))
+	}
+	if err := os.Rename(d.Path, newPath); err != nil {
+		return "", err
+	}
+	return newPath, idx.Rename(d.Path, newPath)
+}
+
+// syncFromFilename rewrites the note's frontmatter to match its filename
+func syncFromFilename(idx *NoteIndex, d *denoteDrift) error {
+	return writeSyncedFrontmatter(idx, d.Path, func(meta *frontmatter.Frontmatter) error {
+		if d.TitleDrift {
+			if err := meta.Set("title", d.Name.ReadableTitle()); err != nil {
+				return err
+			}
+		}
+		if d.TagsDrift {
+			if len(d.FileTags) == 0 {
+				meta.Delete("tags")
+			} else if err := meta.Set("tags", d.FileTags); err != nil {
+				return err
+			}
+		}
+		if d.IDDrift {
+			return meta.Set("identifier", d.Identifier)
+		}
+		return nil
+	})
+}
+
+// writeSyncedFrontmatter applies change to a note's frontmatter and writes
+// it back, leaving the body alone
+func writeSyncedFrontmatter(idx *NoteIndex, path string, change func(*frontmatter.Frontmatter) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	doc, err := frontmatter.Parse(string(content))
+	if err != nil {
+		return err
+	}
+	if doc.Meta == nil {
+		return fmt.Errorf("%s has no frontmatter", filepath.Base(path))
+	}
+	if err := change(doc.Meta); err != nil {
+		return err
+	}
+	updated, err := doc.String()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(updated), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return idx.Update(path)
+}
+
+// runSyncCommand implements `notes-tui sync`: it finds every Denote note
+// whose filename and frontmatter disagree, shows the difference and asks
+// which side wins, or applies --prefer to all of them
+func runSyncCommand(args []string, stdin io.Reader, stdout io.Writer) int {
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	dryRun := flags.Bool("dry-run", false, "Show the differences without changing anything")
+	prefer := flags.String("prefer", "", "Resolve every note the same way: frontmatter or filename")
+	flags.Usage = func() {
+		fmt.Fprintln(stdout, "Usage: notes-tui sync [--dry-run] [--prefer=frontmatter|filename] [directory]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *prefer != "" && *prefer != "frontmatter" && *prefer != "filename" {
+		fmt.Fprintf(stdout, "--prefer: expected frontmatter or filename, got %q\n", *prefer)
+		return 2
+	}
+
+	config := LoadConfig()
+	root := config.NotesDirectory
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	if root == "" {
+		root = "."
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+
+	idx := NewNoteIndex(root, config)
+	if err := idx.Build(); err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+
+	// Every note synced in this run is undone together
+	drifted, synced := 0, 0
+	op := newOperation("sync")
+	journal := openJournal(root)
+	defer func() {
+		if len(op.Changes) == 0 {
+			return
+		}
+		op.Label = fmt.Sprintf("sync of %d notes", synced)
+		if err := journal.commit(op); err != nil {
+			fmt.Fprintf(stdout, "undo history: %v\n", err)
+		}
+	}()
+
+	answers := bufio.NewScanner(stdin)
+	for _, path := range idx.Paths() {
+		rel, _ := filepath.Rel(root, path)
+		drift, err := checkDenoteSync(path, config.denoteOrder())
+		if err != nil {
+			fmt.Fprintf(stdout, "%s: %v\n", rel, err)
+			continue
+		}
+		if drift == nil || !drift.Drifted() {
+			continue
+		}
+		drifted++
+
+		fmt.Fprintf(stdout, "\n%s\n", rel)
+		for _, line := range drift.Diff() {
+			fmt.Fprintln(stdout, "  "+line)
+		}
+		if *dryRun {
+			continue
+		}
+
+		choice := *prefer
+		if choice == "" {
+			fmt.Fprint(stdout, "Keep [f]rontmatter, [n]ame, [s]kip or [q]uit? ")
+			if !answers.Scan() {
+				break
+			}
+			switch strings.ToLower(strings.TrimSpace(answers.Text())) {
+			case "f":
+				choice = "frontmatter"
+			case "n":
+				choice = "filename"
+			case "q":
+				fmt.Fprintf(stdout, "\nSynced %d of %d drifted notes\n", synced, drifted)
+				return 0
+			default:
+				continue
+			}
+		}
+
+		newPath, err := syncNote(idx, op, drift, choice == "frontmatter")
+		if err != nil {
+			fmt.Fprintf(stdout, "  error: %v\n", err)
+			continue
+		}
+		if newPath != path {
+			fmt.Fprintf(stdout, "  renamed to %s\n", filepath.Base(newPath))
+		}
+		synced++
+	}
+
+	if *dryRun {
+		fmt.Fprintf(stdout, "\n%d drifted notes\n", drifted)
+	} else {
+		fmt.Fprintf(stdout, "\nSynced %d of %d drifted notes\n", synced, drifted)
+	}
+	return 0
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// filterKind identifies what a filter in the stack matches on
+type filterKind int
+
+const (
+	filterTask filterKind = iota
+	filterTag
+	filterText
+	filterPeriod
+	filterOld
+	filterTitle
+	filterPath
+	filterModified
+	filterCreated
+)
+
+// noteFilter is one entry in the filter stack. Filters compose: each one
+// narrows the result of the filters before it.
+type noteFilter struct {
+	Kind    filterKind
+	Value   string // tag, search query, path fragment, date prefix or period
+	Days    int    // window for filterOld and filterCreated
+	Content bool   // filterText matches note contents instead of names
+	Negate  bool   // keep the notes the filter would otherwise match
+}
+
+// Label returns the chip text shown for the filter in the header
+func (f noteFilter) Label() string {
+	if f.Negate {
+		switch f.Kind {
+		case filterOld:
+			return fmt.Sprintf("Older than %d days", f.Days)
+		case filterCreated:
+			if f.Value == "" {
+				return fmt.Sprintf("Created before %d days ago", f.Days)
+			}
+		}
+		return "-" + noteFilter{Kind: f.Kind, Value: f.Value, Days: f.Days, Content: f.Content}.Label()
+	}
+
+	switch f.Kind {
+	case filterTask:
+		return "Tasks"
+	case filterTag:
+		return "#" + f.Value
+	case filterText:
+		if f.Content {
+			return fmt.Sprintf("Content: %s", f.Value)
+		}
+		return fmt.Sprintf("Search: %s", f.Value)
+	case filterPeriod:
+		return strings.ToUpper(f.Value[:1]) + f.Value[1:]
+	case filterOld:
+		return fmt.Sprintf("Last %d days", f.Days)
+	case filterTitle:
+		return fmt.Sprintf("Title: %s", f.Value)
+	case filterPath:
+		return fmt.Sprintf("Path: %s", f.Value)
+	case filterModified:
+		return fmt.Sprintf("Modified: %s", f.Value)
+	case filterCreated:
+		if f.Value == "" {
+			return fmt.Sprintf("Created last %d days", f.Days)
+		}
+		return fmt.Sprintf("Created: %s", f.Value)
+	}
+	return ""
+}
+
+// pushFilter adds filters to the stack, skipping any identical one that is
+// already active, then recomputes the filtered list
+func (m *model) pushFilter(filters ...noteFilter) {
+	for _, f := range filters {
+		if !m.hasFilter(f) {
+			m.filters = append(m.filters, f)
+		}
+	}
+	m.applyActiveFilters()
+	m.cursor = 0
+}
+
+// hasFilter reports whether an identical filter is on the stack
+func (m *model) hasFilter(f noteFilter) bool {
+	for _, existing := range m.filters {
+		if existing == f {
+			return true
+		}
+	}
+	return false
+}
+
+// popFilter removes the most recently added filter
+func (m *model) popFilter() bool {
+	if len(m.filters) == 0 {
+		return false
+	}
+	m.filters = m.filters[:len(m.filters)-1]
+	if len(m.filters) == 0 {
+		m.activeView = ""
+	}
+	m.applyActiveFilters()
+	m.cursor = 0
+	return true
+}
+
+// clearFilters empties the filter stack and leaves any saved view
+func (m *model) clearFilters() {
+	m.filters = nil
+	m.activeView = ""
+	m.applyActiveFilters()
+	m.cursor = 0
+}
+
+// filterLabels returns the header chip text for every active filter
+func (m *model) filterLabels() []string {
+	labels := make([]string, len(m.filters))
+	for i, f := range m.filters {
+		labels[i] = f.Label()
+	}
+	return labels
+}
+
+// applyActiveFilters recomputes the filtered list by running every filter on
+// the stack in order, followed by the live search query while searching
+func (m *model) applyActiveFilters() {
+	m.contentMatches = nil
+	m.highlights = nil
+	m.snippetQuery = ""
+
+	files := m.files
+	for _, f := range m.filters {
+		files = m.applyFilter(f, files)
+	}
+
+	// Live search results follow the query being typed; an incomplete
+	// query leaves the list as the stack has it until it parses
+	if m.searchMode {
+		if query, err := parseQuery(m.search.Value(), m.contentSearch); err == nil {
+			for _, f := range query {
+				files = m.applyFilter(f, files)
+			}
+		}
+	}
+
+	m.filtered = files
+}
+
+// applyFilter narrows files by a single filter, keeping their order except
+// for text searches, which rank their results
+func (m *model) applyFilter(f noteFilter, files []string) []string {
+	matched := m.matchFilter(f, files)
+	if !f.Negate {
+		return matched
+	}
+
+	exclude := make(map[string]bool, len(matched))
+	for _, file := range matched {
+		exclude[file] = true
+	}
+	var result []string
+	for _, file := range files {
+		if !exclude[file] {
+			result = append(result, file)
+		}
+	}
+	return result
+}
+
+// matchFilter returns the files a filter matches, ignoring Negate
+func (m *model) matchFilter(f noteFilter, files []string) []string {
+	switch f.Kind {
+	case filterTask:
+		return searchTasks(m.index, files)
+	case filterTag:
+		return searchTag(m.index, files, f.Value)
+	case filterPeriod:
+		period, _ := findPeriod(m.config, f.Value)
+		return searchPeriodicNotes(m.index, files, period)
+	case filterOld:
+		return filterFilesByDaysOld(m.index, files, f.Days)
+	case filterTitle:
+		return filterFilesByTitle(m.index, files, f.Value)
+	case filterPath:
+		return filterFilesByPath(m.index, files, f.Value)
+	case filterModified:
+		return filterFilesByModifiedDate(m.index, files, f.Value)
+	case filterCreated:
+		return filterFilesByCreated(m.index, files, f.Value, f.Days)
+	case filterText:
+		if f.Value == "" {
+			return files
+		}
+		// Only a positive search has matches worth showing
+		if f.Content {
+			result, matches := searchContent(m.index, files, f.Value)
+			if !f.Negate {
+				m.contentMatches, m.highlights, m.snippetQuery = matches, nil, f.Value
+			}
+			return result
+		}
+		result, highlights := filterFiles(m.index, files, f.Value, m.cwd, m.config.ShowTitles)
+		if !f.Negate {
+			m.contentMatches, m.highlights, m.snippetQuery = nil, highlights, ""
+		}
+		return result
+	}
+	return files
+}
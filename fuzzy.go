@@ -0,0 +1,235 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pdxmph/notes-tui/internal/denote"
+)
+
+// Fuzzy scoring weights
+const (
+	fuzzyMatchScore       = 16 // every matched character
+	fuzzyConsecutiveBonus = 12 // character directly follows the previous match
+	fuzzyBoundaryBonus    = 10 // character starts a word (after -, _, /, space, ., or a case change)
+	fuzzyFirstCharBonus   = 6  // character is the first in the target
+	fuzzyTitleBonus       = 4  // character lies in a Denote title segment
+	fuzzyGapOpen          = 3  // penalty for starting a gap between matches
+	fuzzyGapExtend        = 1  // penalty per additional skipped character
+)
+
+// fuzzyResult is the outcome of matching a pattern against a target
+type fuzzyResult struct {
+	Score     int
+	Positions []int // rune offsets of matched characters in the target
+}
+
+// fuzzyMatch scores pattern as a subsequence of target. Every pattern rune
+// must appear in order (case-insensitively); the alignment with the highest
+// score wins, favoring word boundaries, runs of consecutive characters and
+// Denote title segments.
+func fuzzyMatch(pattern, target string) (fuzzyResult, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(target)
+	lower := []rune(strings.ToLower(target))
+	if len(p) == 0 {
+		return fuzzyResult{}, true
+	}
+	if len(p) > len(t) || len(lower) != len(t) {
+		return fuzzyResult{}, false
+	}
+
+	bonus := fuzzyBonuses(t)
+	const none = -1 << 30
+
+	// score[i][j]: best score with p[i] matched at t[j]; from[i][j]: where p[i-1] matched
+	score := make([][]int, len(p))
+	from := make([][]int, len(p))
+	for i := range p {
+		score[i] = make([]int, len(t))
+		from[i] = make([]int, len(t))
+		for j := range t {
+			score[i][j] = none
+			from[i][j] = -1
+		}
+	}
+
+	for j := range t {
+		if lower[j] == p[0] {
+			score[0][j] = fuzzyMatchScore + bonus[j]
+		}
+	}
+
+	for i := 1; i < len(p); i++ {
+		// carry tracks the best previous match ending two or more runes back,
+		// already charged for the gap up to j-1
+		carry, carryFrom := none, -1
+		for j := 1; j < len(t); j++ {
+			if j >= 2 {
+				if carry != none {
+					carry -= fuzzyGapExtend
+				}
+				if prev := score[i-1][j-2]; prev != none && prev-fuzzyGapOpen > carry {
+					carry, carryFrom = prev-fuzzyGapOpen, j-2
+				}
+			}
+			if lower[j] != p[i] {
+				continue
+			}
+
+			best, bestFrom := carry, carryFrom
+			if prev := score[i-1][j-1]; prev != none && prev+fuzzyConsecutiveBonus >= best {
+				best, bestFrom = prev+fuzzyConsecutiveBonus, j-1
+			}
+			if best == none {
+				continue
+			}
+			score[i][j] = best + fuzzyMatchScore + bonus[j]
+			from[i][j] = bestFrom
+		}
+	}
+
+	// Pick the best final position and walk back to recover the alignment
+	last := len(p) - 1
+	bestScore, bestEnd := none, -1
+	for j := range t {
+		if score[last][j] > bestScore {
+			bestScore, bestEnd = score[last][j], j
+		}
+	}
+	if bestEnd < 0 {
+		return fuzzyResult{}, false
+	}
+
+	positions := make([]int, len(p))
+	j := bestEnd
+	for i := last; i >= 0; i-- {
+		positions[i] = j
+		j = from[i][j]
+	}
+	return fuzzyResult{Score: bestScore, Positions: positions}, true
+}
+
+// fuzzyBonuses precomputes the positional bonus for each rune of target
+func fuzzyBonuses(t []rune) []int {
+	bonus := make([]int, len(t))
+
+	// Locate the Denote title segment in the filename part of the target
+	base := len(t) - len([]rune(filepath.Base(string(t))))
+	titleStart, titleEnd := -1, -1
+	name := string(t[base:])
+	if denoteName, ok := denote.Parse(name); ok && denoteName.Title != "" {
+		if idx := strings.Index(name, "--"+denoteName.Title); idx >= 0 {
+			titleStart = base + len([]rune(name[:idx+2]))
+			titleEnd = titleStart + len([]rune(denoteName.Title))
+		}
+	}
+
+	for j, r := range t {
+		switch {
+		case j == 0:
+			bonus[j] = fuzzyBoundaryBonus + fuzzyFirstCharBonus
+		case strings.ContainsRune("-_/ .", t[j-1]):
+			bonus[j] = fuzzyBoundaryBonus
+		case unicode.IsUpper(r) && unicode.IsLower(t[j-1]):
+			bonus[j] = fuzzyBoundaryBonus
+		}
+		if j >= titleStart && j < titleEnd {
+			bonus[j] += fuzzyTitleBonus
+		}
+	}
+	return bonus
+}
+
+// fuzzyMatchTerms matches every whitespace-separated term of query against
+// target, summing scores. All terms must match.
+func fuzzyMatchTerms(query, target string) (fuzzyResult, bool) {
+	var total fuzzyResult
+	seen := make(map[int]bool)
+	for _, term := range strings.Fields(query) {
+		result, ok := fuzzyMatch(term, target)
+		if !ok {
+			return fuzzyResult{}, false
+		}
+		total.Score += result.Score
+		for _, pos := range result.Positions {
+			if !seen[pos] {
+				seen[pos] = true
+				total.Positions = append(total.Positions, pos)
+			}
+		}
+	}
+	sort.Ints(total.Positions)
+	return total, true
+}
+
+// recencyBonus favors recently modified notes when scores are close
+func recencyBonus(modTime time.Time) int {
+	age := time.Since(modTime)
+	switch {
+	case age < 24*time.Hour:
+		return 12
+	case age < 7*24*time.Hour:
+		return 8
+	case age < 30*24*time.Hour:
+		return 4
+	case age < 90*24*time.Hour:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Filter files based on a fuzzy search query, ranked best match first. Each
+// file is matched on its path relative to cwd and, when titles are shown, on
+// its displayed title. Highlight positions are returned for displayed text.
+func filterFiles(idx *NoteIndex, files []string, query, cwd string, showTitles bool) ([]string, map[string][]int) {
+	highlights := make(map[string][]int)
+	if strings.TrimSpace(query) == "" {
+		return files, highlights
+	}
+
+	type ranked struct {
+		path  string
+		score int
+	}
+	var results []ranked
+
+	for _, file := range files {
+		relative := getDisplayName(file, cwd)
+		best, ok := fuzzyMatchTerms(query, relative)
+		displayed := !showTitles
+
+		if showTitles {
+			title := getEnhancedDisplayName(idx, file, cwd, true)
+			if result, titleOK := fuzzyMatchTerms(query, title); titleOK && (!ok || result.Score >= best.Score) {
+				best, ok, displayed = result, true, true
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		if modTime, found := idx.ModTime(file); found {
+			best.Score += recencyBonus(modTime)
+		}
+		if displayed {
+			highlights[file] = best.Positions
+		}
+		results = append(results, ranked{path: file, score: best.Score})
+	}
+
+	// Stable so equal scores keep the current sort order
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	filtered := make([]string, len(results))
+	for i, r := range results {
+		filtered[i] = r.path
+	}
+	return filtered, highlights
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pdxmph/notes-tui/internal/denote"
+	"github.com/pdxmph/notes-tui/internal/frontmatter"
+)
+
+// NoteEntry holds everything we know about a note after parsing it once.
+// The content itself isn't kept; full-text search reads it from disk.
+type NoteEntry struct {
+	Path        string            // absolute path to the note
+	Identifier  string            // Denote identifier (YYYYMMDDTHHMMSS), if any
+	Title       string            // title from frontmatter or first heading
+	Tags        []string          // tags from frontmatter, Denote filename and inline #tags
+	Frontmatter map[string]string // raw top-level frontmatter values
+	ModTime     time.Time         // file modification time
+	OpenTasks   int               // number of unchecked tasks
+	Tasks       []noteTask        // checkbox lines, open and done
+	Links       []noteLink        // links to other notes, unresolved
+}
+
+// NoteIndex is an in-memory index of every note under the notes directory.
+// It is built once at startup and then kept current incrementally as notes
+// are created, renamed, edited and deleted, so the UI never has to walk the
+// tree again.
+type NoteIndex struct {
+	mu      sync.RWMutex
+	root    string
+	config  Config
+	entries map[string]*NoteEntry
+	paths   []string // sorted paths, rebuilt lazily
+	dirty   bool
+
+	links      *linkGraph // resolved backlinks, rebuilt lazily
+	linksDirty bool
+}
+
+// NewNoteIndex creates an empty index rooted at dir
+func NewNoteIndex(dir string, config Config) *NoteIndex {
+	return &NoteIndex{
+		root:    dir,
+		config:  config,
+		entries: make(map[string]*NoteEntry),
+	}
+}
+
+// Build walks the notes directory and parses every note in parallel
+func (idx *NoteIndex) Build() error {
+	files, err := findMarkdownFiles(idx.root, idx.config)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*NoteEntry, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	workers := runtime.NumCPU()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if entry, err := parseNoteEntry(files[i]); err == nil {
+					entries[i] = entry
+				}
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.entries = make(map[string]*NoteEntry, len(entries))
+	for _, entry := range entries {
+		if entry != nil {
+			idx.entries[entry.Path] = entry
+		}
+	}
+	idx.dirty = true
+	idx.linksDirty = true
+	return nil
+}
+
+// Update (re)parses a single note, adding it to the index if it is new.
+// Notes carrying a filtered tag are dropped instead.
+func (idx *NoteIndex) Update(path string) error {
+	if !isNoteFile(path) || idx.isExcluded(path) {
+		idx.Remove(path)
+		return nil
+	}
+
+	entry, err := parseNoteEntry(path)
+	if err != nil {
+		idx.Remove(path)
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	old, exists := idx.entries[path]
+	if !exists {
+		idx.dirty = true
+	}
+
+	// An edit that keeps the note's identifier and title can't change
+	// where any other note's links go, so only its own links are redone
+	if exists && idx.links != nil && !idx.linksDirty &&
+		old.Identifier == entry.Identifier && old.Title == entry.Title {
+		idx.links.removeLinks(path, old)
+		idx.entries[path] = entry
+		idx.links.addLinks(path, entry)
+		idx.links.sortBacklinks()
+		return nil
+	}
+	idx.entries[path] = entry
+	idx.linksDirty = true
+	return nil
+}
+
+// Remove drops a note from the index
+func (idx *NoteIndex) Remove(path string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, exists := idx.entries[path]; exists {
+		delete(idx.entries, path)
+		idx.dirty = true
+		idx.linksDirty = true
+	}
+}
+
+// Rename moves an entry to its new path and reparses it
+func (idx *NoteIndex) Rename(oldPath, newPath string) error {
+	idx.Remove(oldPath)
+	return idx.Update(newPath)
+}
+
+// Refresh brings the index in line with whatever is now on disk at path,
+// which may be a note, a directory, or something that no longer exists
+func (idx *NoteIndex) Refresh(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		idx.removeTree(path)
+		return
+	}
+
+	if info.IsDir() {
+		files, err := findMarkdownFiles(path, idx.config)
+		if err != nil {
+			return
+		}
+		for _, file := range files {
+			idx.Update(file)
+		}
+		return
+	}
+
+	idx.Update(path)
+}
+
+// removeTree drops a path and every entry beneath it
+func (idx *NoteIndex) removeTree(path string) {
+	prefix := path + string(filepath.Separator)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for entryPath := range idx.entries {
+		if entryPath == path || strings.HasPrefix(entryPath, prefix) {
+			delete(idx.entries, entryPath)
+			idx.dirty = true
+			idx.linksDirty = true
+		}
+	}
+}
+
+// Get returns the entry for a path
+func (idx *NoteIndex) Get(path string) (*NoteEntry, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	entry, ok := idx.entries[path]
+	return entry, ok
+}
+
+// Paths returns all indexed note paths in lexical order
+func (idx *NoteIndex) Paths() []string {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.dirty || idx.paths == nil {
+		paths := make([]string, 0, len(idx.entries))
+		for path := range idx.entries {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		idx.paths = paths
+		idx.dirty = false
+	}
+
+	result := make([]string, len(idx.paths))
+	copy(result, idx.paths)
+	return result
+}
+
+// Select returns the files whose entries satisfy match, preserving order
+func (idx *NoteIndex) Select(files []string, match func(*NoteEntry) bool) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var selected []string
+	for _, file := range files {
+		if entry, ok := idx.entries[file]; ok && match(entry) {
+			selected = append(selected, file)
+		}
+	}
+	return selected
+}
+
+// Entries returns a snapshot of all entries in path order
+func (idx *NoteIndex) Entries() []*NoteEntry {
+	paths := idx.Paths()
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	entries := make([]*NoteEntry, 0, len(paths))
+	for _, path := range paths {
+		if entry, ok := idx.entries[path]; ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// ModTime returns the indexed modification time, falling back to stat
+func (idx *NoteIndex) ModTime(path string) (time.Time, bool) {
+	if entry, ok := idx.Get(path); ok {
+		return entry.ModTime, true
+	}
+	if stat, err := os.Stat(path); err == nil {
+		return stat.ModTime(), true
+	}
+	return time.Time{}, false
+}
+
+// isExcluded reports whether a path carries one of the configured filtered tags
+func (idx *NoteIndex) isExcluded(path string) bool {
+	if len(idx.config.FilteredTags) == 0 {
+		return false
+	}
+	for _, fileTag := range extractDenoteTags(path) {
+		for _, filteredTag := range idx.config.FilteredTags {
+			if fileTag == filteredTag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var (
+	// markdownExtensions are always notes
+	markdownExtensions = []string{".md", ".markdown"}
+	// denoteExtensions are notes only when they have a Denote name
+	denoteExtensions = []string{".org", ".txt"}
+)
+
+// isNoteFile reports whether a path is a note: a markdown file, or a
+// Denote-named org or plain text file
+func isNoteFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if slices.Contains(markdownExtensions, ext) {
+		return true
+	}
+	if slices.Contains(denoteExtensions, ext) {
+		_, ok := denote.Parse(filepath.Base(path))
+		return ok
+	}
+	return false
+}
+
+// parseNoteEntry reads a note once and extracts all indexed metadata
+func parseNoteEntry(path string) (*NoteEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	text := string(content)
+	fields, fmTags := parseFrontmatter(text)
+
+	entry := &NoteEntry{
+		Path:        path,
+		Title:       extractTitleFromContent(text),
+		Frontmatter: fields,
+		ModTime:     info.ModTime(),
+	}
+
+	// Denote identifier from the filename, then from frontmatter
+	if name, ok := denote.Parse(filepath.Base(path)); ok {
+		entry.Identifier = name.Identifier
+	} else if id := fields["identifier"]; id != "" {
+		entry.Identifier = id
+	}
+
+	// Merge frontmatter, filename and inline tags without duplicates
+	entry.Tags = extractTags(path, text, fmTags)
+	entry.Links = extractLinks(text)
+	entry.Tasks = extractTasks(text)
+	for _, task := range entry.Tasks {
+		if !task.Done {
+			entry.OpenTasks++
+		}
+	}
+
+	return entry, nil
+}
+
+// eachBodyLine calls fn with the 1-based number and text of every line of
+// content outside frontmatter and fenced code blocks
+func eachBodyLine(content string, fn func(num int, line string)) {
+	_, _, body, start := frontmatter.Split(content)
+	lines := strings.Split(body, "\n")
+
+	var fence string
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		fn(start+i+1, strings.TrimSuffix(lines[i], "\r"))
+	}
+}
+
+// parseFrontmatter returns the top-level frontmatter values as text and
+// the tags listed there. Malformed frontmatter gives neither.
+func parseFrontmatter(content string) (map[string]string, []string) {
+	doc, err := frontmatter.Parse(content)
+	if err != nil || doc.Meta == nil {
+		return map[string]string{}, nil
+	}
+	return doc.Meta.Map(), doc.Meta.Strings("tags")
+}
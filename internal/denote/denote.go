@@ -0,0 +1,276 @@
+// Package denote parses and formats Denote filenames:
+//
+//	20250623T093045==sig--the-title__tag1_tag2.md
+//
+// A name is an identifier (YYYYMMDDTHHMMSS) and optional signature (==),
+// title (--) and keywords (__) in a configurable order, then an extension.
+// When the identifier isn't the first component it is marked with @@.
+package denote
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Component is one part of a Denote filename
+type Component string
+
+const (
+	Identifier Component = "identifier"
+	Signature  Component = "signature"
+	Title      Component = "title"
+	Keywords   Component = "keywords"
+)
+
+// DefaultOrder is Denote's standard component order
+var DefaultOrder = []Component{Identifier, Signature, Title, Keywords}
+
+// markers introduce each component in a filename
+var markers = map[string]Component{
+	"@@": Identifier,
+	"==": Signature,
+	"--": Title,
+	"__": Keywords,
+}
+
+// marker returns the separator that introduces c
+func (c Component) marker() string {
+	for marker, component := range markers {
+		if component == c {
+			return marker
+		}
+	}
+	return ""
+}
+
+// ParseOrder reads a component order from configuration. Every component
+// must appear exactly once; an empty list gives DefaultOrder.
+func ParseOrder(names []string) ([]Component, error) {
+	if len(names) == 0 {
+		return DefaultOrder, nil
+	}
+	var order []Component
+	for _, name := range names {
+		c := Component(strings.ToLower(strings.TrimSpace(name)))
+		if !slices.Contains(DefaultOrder, c) {
+			return nil, fmt.Errorf("unknown Denote component %q (expected identifier, signature, title or keywords)", name)
+		}
+		if slices.Contains(order, c) {
+			return nil, fmt.Errorf("Denote component %q listed twice", name)
+		}
+		order = append(order, c)
+	}
+	if len(order) != len(DefaultOrder) {
+		return nil, fmt.Errorf("Denote components must list identifier, signature, title and keywords")
+	}
+	return order, nil
+}
+
+// Name is a parsed Denote filename. Signature, Title and Keywords hold the
+// slugged forms found in the filename.
+type Name struct {
+	Identifier string   // YYYYMMDDTHHMMSS
+	Signature  string   // words joined with =
+	Title      string   // words joined with -
+	Keywords   []string // each lowercase, joined with _ in the filename
+	Extension  string   // e.g. ".md", ".org" or ".txt.gpg"
+}
+
+// encryptedExtensions are added after a note's own extension, as in
+// .md.gpg, and are kept together with it
+var encryptedExtensions = []string{".gpg", ".age"}
+
+// SplitExtension splits a filename at its extension. Only the last dot
+// counts, so titles may hold dots, except that an encrypted note keeps
+// the extension before its .gpg or .age too.
+func SplitExtension(base string) (stem, ext string) {
+	dot := strings.LastIndex(base, ".")
+	if dot <= 0 {
+		return base, ""
+	}
+	stem, ext = base[:dot], base[dot:]
+	if slices.Contains(encryptedExtensions, strings.ToLower(ext)) {
+		if inner := strings.LastIndex(stem, "."); inner > 0 {
+			stem, ext = stem[:inner], stem[inner:]+ext
+		}
+	}
+	return stem, ext
+}
+
+// identifierPattern matches a Denote identifier
+var identifierPattern = regexp.MustCompile(`^\d{8}T\d{6}$`)
+
+// IsIdentifier reports whether s is a valid Denote identifier
+func IsIdentifier(s string) bool {
+	if !identifierPattern.MatchString(s) {
+		return false
+	}
+	_, err := time.Parse("20060102T150405", s)
+	return err == nil
+}
+
+// IdentifierFor returns the identifier for a moment
+func IdentifierFor(t time.Time) string {
+	return t.Format("20060102T150405")
+}
+
+// Parse reads a Denote filename, which may include a directory. Components
+// may come in any order; the identifier is required.
+func Parse(filename string) (Name, bool) {
+	base := filename
+	if slash := strings.LastIndexAny(base, `/\`); slash >= 0 {
+		base = base[slash+1:]
+	}
+
+	var n Name
+	stem, ext := SplitExtension(base)
+	n.Extension = ext
+
+	pos := 0
+	if len(stem) >= 15 && IsIdentifier(stem[:15]) {
+		n.Identifier = stem[:15]
+		pos = 15
+	}
+
+	seen := map[Component]bool{}
+	if n.Identifier != "" {
+		seen[Identifier] = true
+	}
+	for pos < len(stem) {
+		if pos+2 > len(stem) {
+			return Name{}, false
+		}
+		component, ok := markers[stem[pos:pos+2]]
+		if !ok || seen[component] {
+			return Name{}, false
+		}
+		seen[component] = true
+
+		end := nextMarker(stem, pos+2)
+		value := stem[pos+2 : end]
+		switch component {
+		case Identifier:
+			if !IsIdentifier(value) {
+				return Name{}, false
+			}
+			n.Identifier = value
+		case Signature:
+			n.Signature = value
+		case Title:
+			n.Title = value
+		case Keywords:
+			for _, keyword := range strings.Split(value, "_") {
+				if keyword != "" {
+					n.Keywords = append(n.Keywords, keyword)
+				}
+			}
+		}
+		pos = end
+	}
+
+	if n.Identifier == "" {
+		return Name{}, false
+	}
+	return n, true
+}
+
+// nextMarker returns the position of the first component marker at or
+// after start, or len(s)
+func nextMarker(s string, start int) int {
+	for i := start; i+2 <= len(s); i++ {
+		if _, ok := markers[s[i:i+2]]; ok {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// Format writes the name with its components in order. Empty components
+// are left out, and the identifier is marked with @@ unless it comes first.
+func (n Name) Format(order []Component) string {
+	if len(order) == 0 {
+		order = DefaultOrder
+	}
+
+	var b strings.Builder
+	for _, c := range order {
+		var value string
+		switch c {
+		case Identifier:
+			value = n.Identifier
+		case Signature:
+			value = n.Signature
+		case Title:
+			value = n.Title
+		case Keywords:
+			value = strings.Join(n.Keywords, "_")
+		}
+		if value == "" {
+			continue
+		}
+		if c != Identifier || b.Len() > 0 {
+			b.WriteString(c.marker())
+		}
+		b.WriteString(value)
+	}
+	b.WriteString(n.Extension)
+	return b.String()
+}
+
+// Time returns the moment the identifier names
+func (n Name) Time() (time.Time, bool) {
+	t, err := time.ParseInLocation("20060102T150405", n.Identifier, time.Local)
+	return t, err == nil
+}
+
+// ReadableTitle turns the title slug back into words, capitalising each
+func (n Name) ReadableTitle() string {
+	words := strings.Split(n.Title, "-")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+var (
+	slugInvalid   = regexp.MustCompile(`[^a-z0-9-]+`)
+	slugHyphens   = regexp.MustCompile(`-+`)
+	signatureRuns = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// SlugTitle turns a title into its filename form: lowercase letters,
+// digits and single hyphens
+func SlugTitle(title string) string {
+	slug := strings.ReplaceAll(strings.ToLower(title), " ", "-")
+	slug = slugInvalid.ReplaceAllString(slug, "")
+	slug = slugHyphens.ReplaceAllString(slug, "-")
+	return strings.Trim(slug, "-")
+}
+
+// SlugKeyword turns a tag into a keyword. Keywords are slugged like titles
+// so they can never contain the _ that separates them.
+func SlugKeyword(tag string) string {
+	return SlugTitle(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// SlugSignature turns a signature into its filename form, with words
+// joined by =
+func SlugSignature(signature string) string {
+	return strings.Trim(signatureRuns.ReplaceAllString(strings.ToLower(signature), "="), "=")
+}
+
+// KeywordsFor slugs tags into keywords, dropping empty and repeated ones
+func KeywordsFor(tags []string) []string {
+	var keywords []string
+	for _, tag := range tags {
+		if keyword := SlugKeyword(tag); keyword != "" && !slices.Contains(keywords, keyword) {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
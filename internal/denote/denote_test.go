@@ -0,0 +1,122 @@
+package denote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAndFormatRoundTrip(t *testing.T) {
+	signatureFirst := []Component{Signature, Identifier, Title, Keywords}
+	titleFirst := []Component{Title, Keywords, Signature, Identifier}
+	keywordsFirst := []Component{Keywords, Title, Identifier, Signature}
+
+	tests := []struct {
+		name     string
+		filename string
+		order    []Component
+		want     Name
+	}{
+		{"identifier only", "20250623T093045.md", nil,
+			Name{Identifier: "20250623T093045", Extension: ".md"}},
+		{"title", "20250623T093045--meeting-notes.md", nil,
+			Name{Identifier: "20250623T093045", Title: "meeting-notes", Extension: ".md"}},
+		{"one keyword", "20250623T093045--plan__work.md", nil,
+			Name{Identifier: "20250623T093045", Title: "plan", Keywords: []string{"work"}, Extension: ".md"}},
+		{"several keywords", "20250623T093045--plan__work_planning_q3.md", nil,
+			Name{Identifier: "20250623T093045", Title: "plan", Keywords: []string{"work", "planning", "q3"}, Extension: ".md"}},
+		{"signature", "20250623T093045==1a=2--plan__work.md", nil,
+			Name{Identifier: "20250623T093045", Signature: "1a=2", Title: "plan", Keywords: []string{"work"}, Extension: ".md"}},
+		{"signature only", "20250623T093045==draft.md", nil,
+			Name{Identifier: "20250623T093045", Signature: "draft", Extension: ".md"}},
+		{"signature first", "==draft@@20250623T093045--plan__work.md", signatureFirst,
+			Name{Identifier: "20250623T093045", Signature: "draft", Title: "plan", Keywords: []string{"work"}, Extension: ".md"}},
+		{"title first", "--plan__work_home==draft@@20250623T093045.md", titleFirst,
+			Name{Identifier: "20250623T093045", Signature: "draft", Title: "plan", Keywords: []string{"work", "home"}, Extension: ".md"}},
+		{"keywords first", "__work--plan@@20250623T093045==draft.md", keywordsFirst,
+			Name{Identifier: "20250623T093045", Signature: "draft", Title: "plan", Keywords: []string{"work"}, Extension: ".md"}},
+		{"markdown", "20250623T093045--plan.markdown", nil,
+			Name{Identifier: "20250623T093045", Title: "plan", Extension: ".markdown"}},
+		{"org", "20250623T093045--plan__work.org", nil,
+			Name{Identifier: "20250623T093045", Title: "plan", Keywords: []string{"work"}, Extension: ".org"}},
+		{"text", "20250623T093045--plan.txt", nil,
+			Name{Identifier: "20250623T093045", Title: "plan", Extension: ".txt"}},
+		{"gpg", "20250623T093045--plan__secret.md.gpg", nil,
+			Name{Identifier: "20250623T093045", Title: "plan", Keywords: []string{"secret"}, Extension: ".md.gpg"}},
+		{"age", "20250623T093045--plan.org.age", nil,
+			Name{Identifier: "20250623T093045", Title: "plan", Extension: ".org.age"}},
+		{"dot in title", "20250623T093045--v1.2-notes.md", nil,
+			Name{Identifier: "20250623T093045", Title: "v1.2-notes", Extension: ".md"}},
+		{"dot in title, encrypted", "20250623T093045--v1.2-notes.md.gpg", nil,
+			Name{Identifier: "20250623T093045", Title: "v1.2-notes", Extension: ".md.gpg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse("notes/" + tt.filename)
+			if !ok {
+				t.Fatalf("Parse(%q) failed", tt.filename)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.filename, got, tt.want)
+			}
+			if formatted := got.Format(tt.order); formatted != tt.filename {
+				t.Errorf("Format = %q, want %q", formatted, tt.filename)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, filename := range []string{
+		"meeting-notes.md",
+		"project__work.md",
+		"2025-06-23--plan.md",
+		"20251323T093045--plan.md",
+		"20250623T093045-plan.md",
+		"20250623T093045--plan--again.md",
+		"--plan__work.md",
+		"--plan@@2025.md",
+	} {
+		if name, ok := Parse(filename); ok {
+			t.Errorf("Parse(%q) = %+v, want failure", filename, name)
+		}
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	order, err := ParseOrder(nil)
+	if err != nil || !reflect.DeepEqual(order, DefaultOrder) {
+		t.Errorf("ParseOrder(nil) = %v, %v", order, err)
+	}
+	order, err = ParseOrder([]string{"Title", " keywords ", "signature", "identifier"})
+	if err != nil || !reflect.DeepEqual(order, []Component{Title, Keywords, Signature, Identifier}) {
+		t.Errorf("ParseOrder = %v, %v", order, err)
+	}
+	for _, names := range [][]string{
+		{"identifier", "title"},
+		{"identifier", "title", "title", "keywords"},
+		{"identifier", "signature", "title", "tags"},
+	} {
+		if _, err := ParseOrder(names); err == nil {
+			t.Errorf("ParseOrder(%q) succeeded", names)
+		}
+	}
+}
+
+func TestFormatLeavesOutEmptyComponents(t *testing.T) {
+	name := Name{Identifier: "20250623T093045", Keywords: []string{"work"}, Extension: ".org"}
+	if got := name.Format([]Component{Title, Identifier, Keywords, Signature}); got != "20250623T093045__work.org" {
+		t.Errorf("Format = %q", got)
+	}
+}
+
+func TestSlugs(t *testing.T) {
+	if got := SlugTitle("  Q3 Plan: Draft #2 "); got != "q3-plan-draft-2" {
+		t.Errorf("SlugTitle = %q", got)
+	}
+	if got := SlugSignature("Draft 1a"); got != "draft=1a" {
+		t.Errorf("SlugSignature = %q", got)
+	}
+	if got := KeywordsFor([]string{"#Work", "work", "long_tag", ""}); !reflect.DeepEqual(got, []string{"work", "longtag"}) {
+		t.Errorf("KeywordsFor = %q", got)
+	}
+}
@@ -0,0 +1,444 @@
+// Package frontmatter reads and writes the metadata block at the top of a
+// note: YAML between --- lines or TOML between +++ lines. Values are edited
+// in place, so keys the app doesn't know about, their order and (for YAML)
+// their comments and quoting survive a rewrite.
+package frontmatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the syntax of a frontmatter block
+type Format int
+
+const (
+	None Format = iota
+	YAML        // delimited by ---
+	TOML        // delimited by +++
+)
+
+// delimiter returns the line that opens and closes a block of the format
+func (f Format) delimiter() string {
+	if f == TOML {
+		return "+++"
+	}
+	return "---"
+}
+
+// Split finds the frontmatter block at the top of content without parsing
+// it. It returns the block's format and text (without the delimiters), the
+// body after it, and the 0-based line on which the body starts. Content
+// without a closed block is all body.
+func Split(content string) (format Format, block, body string, bodyLine int) {
+	first, rest, found := strings.Cut(content, "\n")
+	if !found {
+		return None, "", content, 0
+	}
+	switch strings.TrimSpace(first) {
+	case "---":
+		format = YAML
+	case "+++":
+		format = TOML
+	default:
+		return None, "", content, 0
+	}
+
+	start := len(first) + 1
+	offset := start
+	for line := 1; ; line++ {
+		current, next, more := strings.Cut(rest, "\n")
+		if strings.TrimSpace(current) == format.delimiter() {
+			return format, content[start:offset], next, line + 1
+		}
+		if !more {
+			return None, "", content, 0
+		}
+		offset += len(current) + 1
+		rest = next
+	}
+}
+
+// Has reports whether content starts with a frontmatter block
+func Has(content string) bool {
+	format, _, _, _ := Split(content)
+	return format != None
+}
+
+// Body returns content without its frontmatter block
+func Body(content string) string {
+	_, _, body, _ := Split(content)
+	return body
+}
+
+// Document is a note split into its frontmatter and body
+type Document struct {
+	Meta *Frontmatter // nil when the note has none
+	Body string
+}
+
+// Parse splits content into frontmatter and body and parses the
+// frontmatter. On a syntax error the body is still returned, with Meta nil.
+func Parse(content string) (*Document, error) {
+	format, block, body, _ := Split(content)
+	doc := &Document{Body: body}
+	if format == None {
+		return doc, nil
+	}
+
+	var err error
+	if format == TOML {
+		doc.Meta, err = parseTOML(block)
+	} else {
+		doc.Meta, err = parseYAML(block)
+	}
+	return doc, err
+}
+
+// String joins the frontmatter and body back into a note
+func (d *Document) String() (string, error) {
+	if d.Meta == nil {
+		return d.Body, nil
+	}
+	block, err := d.Meta.Encode()
+	if err != nil {
+		return "", err
+	}
+	return block + d.Body, nil
+}
+
+// Frontmatter is an ordered set of top-level keys
+type Frontmatter struct {
+	format Format
+	node   *yaml.Node  // YAML: the mapping
+	fields []tomlField // TOML: the keys in order
+}
+
+// New returns an empty frontmatter block of the given format
+func New(format Format) *Frontmatter {
+	if format == TOML {
+		return &Frontmatter{format: TOML}
+	}
+	return &Frontmatter{format: YAML, node: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}}
+}
+
+// Format returns the block's syntax
+func (f *Frontmatter) Format() Format {
+	return f.format
+}
+
+// parseYAML reads a YAML block, which must be a mapping
+func parseYAML(block string) (*Frontmatter, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(block), &doc); err != nil {
+		return nil, fmt.Errorf("frontmatter: %w", err)
+	}
+	f := New(YAML)
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return f, nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("frontmatter: expected key: value pairs")
+	}
+	f.node = doc.Content[0]
+	return f, nil
+}
+
+// Keys returns the top-level keys in order
+func (f *Frontmatter) Keys() []string {
+	var keys []string
+	if f.format == TOML {
+		for _, field := range f.fields {
+			keys = append(keys, field.Key)
+		}
+		return keys
+	}
+	for i := 0; i+1 < len(f.node.Content); i += 2 {
+		keys = append(keys, f.node.Content[i].Value)
+	}
+	return keys
+}
+
+// Has reports whether key is present
+func (f *Frontmatter) Has(key string) bool {
+	if f.format == TOML {
+		return f.tomlIndex(key) >= 0
+	}
+	return f.yamlIndex(key) >= 0
+}
+
+// Get returns a value as text: scalars as written (without quotes), lists
+// joined with ", ". Nested tables give "".
+func (f *Frontmatter) Get(key string) string {
+	if f.format == TOML {
+		if i := f.tomlIndex(key); i >= 0 {
+			return tomlText(f.fields[i].Value)
+		}
+		return ""
+	}
+	i := f.yamlIndex(key)
+	if i < 0 {
+		return ""
+	}
+	value := f.node.Content[i+1]
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if value.Tag == "!!null" {
+			return ""
+		}
+		return value.Value
+	case yaml.SequenceNode:
+		return strings.Join(f.Strings(key), ", ")
+	}
+	return ""
+}
+
+// Strings returns a list value. A single string is split on commas, so
+// both "tags: [a, b]" and "tags: a, b" give two tags.
+func (f *Frontmatter) Strings(key string) []string {
+	var values []string
+	add := func(s string) {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+
+	if f.format == TOML {
+		i := f.tomlIndex(key)
+		if i < 0 {
+			return nil
+		}
+		switch v := f.fields[i].Value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				add(tomlText(item))
+			}
+		case string:
+			for _, part := range strings.Split(v, ",") {
+				add(part)
+			}
+		}
+		return values
+	}
+
+	i := f.yamlIndex(key)
+	if i < 0 {
+		return nil
+	}
+	value := f.node.Content[i+1]
+	switch value.Kind {
+	case yaml.SequenceNode:
+		for _, item := range value.Content {
+			if item.Kind == yaml.ScalarNode {
+				add(item.Value)
+			}
+		}
+	case yaml.ScalarNode:
+		if value.Tag != "!!null" {
+			for _, part := range strings.Split(value.Value, ",") {
+				add(part)
+			}
+		}
+	}
+	return values
+}
+
+// dateFormats are the date and time layouts Time understands
+var dateFormats = []string{
+	"2006-01-02T15:04:05Z07:00", // ISO 8601 with timezone
+	"2006-01-02T15:04:05",       // ISO 8601 without timezone
+	"2006-01-02 15:04:05",       // Space separated datetime
+	"2006-01-02 15:04",          // Space separated, no seconds
+	"2006-01-02",                // Date only
+	"01/02/2006",                // US format
+	"02/01/2006",                // European format
+}
+
+// Time parses a date value
+func (f *Frontmatter) Time(key string) (time.Time, bool) {
+	if f.format == TOML {
+		if i := f.tomlIndex(key); i >= 0 {
+			if t, ok := f.fields[i].Value.(time.Time); ok {
+				return t, true
+			}
+		}
+	}
+	value := strings.TrimSpace(f.Get(key))
+	for _, layout := range dateFormats {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Map returns every top-level value as text, as Get gives it
+func (f *Frontmatter) Map() map[string]string {
+	values := make(map[string]string)
+	for _, key := range f.Keys() {
+		values[key] = f.Get(key)
+	}
+	return values
+}
+
+// Set replaces a value, keeping its place, or adds it at the end. Strings
+// are always written as strings, quoted when they would otherwise read as
+// something else; lists are written inline.
+func (f *Frontmatter) Set(key string, value interface{}) error {
+	if f.format == TOML {
+		f.setTOML(key, value)
+		return nil
+	}
+
+	node := &yaml.Node{}
+	switch v := value.(type) {
+	case string:
+		node = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+	case []string:
+		node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, item := range v {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+		}
+	default:
+		if err := node.Encode(value); err != nil {
+			return fmt.Errorf("frontmatter: %s: %w", key, err)
+		}
+	}
+
+	if i := f.yamlIndex(key); i >= 0 {
+		old := f.node.Content[i+1]
+		// Keep how the value was written where the kind hasn't changed
+		if old.Kind == node.Kind && node.Kind == yaml.SequenceNode {
+			node.Style = old.Style
+		}
+		if old.Kind == node.Kind && node.Kind == yaml.ScalarNode && old.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0 {
+			node.Style = old.Style
+		}
+		node.LineComment = old.LineComment
+		f.node.Content[i+1] = node
+		return nil
+	}
+	f.node.Content = append(f.node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		node)
+	return nil
+}
+
+// SetText sets a value from text as a user typed it. Numbers, booleans and
+// dates stay unquoted so they keep their type; anything else is a string.
+func (f *Frontmatter) SetText(key, text string) error {
+	if f.format == TOML {
+		var old interface{}
+		if i := f.tomlIndex(key); i >= 0 {
+			old = f.fields[i].Value
+		}
+		f.setTOML(key, tomlValue(text, old))
+		return nil
+	}
+	if err := f.Set(key, text); err != nil {
+		return err
+	}
+	// Let YAML resolve the type; the encoder still quotes strings that need it
+	node := f.node.Content[f.yamlIndex(key)+1]
+	var probe interface{}
+	if err := yaml.Unmarshal([]byte(text), &probe); err == nil {
+		switch probe.(type) {
+		case int, int64, uint64, float64, bool, time.Time:
+			node.Tag = ""
+			node.Style &^= yaml.SingleQuotedStyle | yaml.DoubleQuotedStyle
+		}
+	}
+	return nil
+}
+
+// Scalar reports whether key holds a single value rather than a list or table
+func (f *Frontmatter) Scalar(key string) bool {
+	if f.format == TOML {
+		i := f.tomlIndex(key)
+		if i < 0 {
+			return false
+		}
+		switch f.fields[i].Value.(type) {
+		case []interface{}, map[string]interface{}, []map[string]interface{}:
+			return false
+		}
+		return true
+	}
+	i := f.yamlIndex(key)
+	return i >= 0 && f.node.Content[i+1].Kind == yaml.ScalarNode
+}
+
+// SetDate sets a value to a bare date, e.g. date: 2025-06-23
+func (f *Frontmatter) SetDate(key string, t time.Time) error {
+	if f.format == TOML {
+		f.setTOML(key, tomlDate(t))
+		return nil
+	}
+	if err := f.Set(key, t.Format("2006-01-02")); err != nil {
+		return err
+	}
+	// A plain date reads back as a timestamp, so leave it unquoted
+	node := f.node.Content[f.yamlIndex(key)+1]
+	node.Tag = "!!timestamp"
+	node.Style = 0
+	return nil
+}
+
+// Delete removes a key
+func (f *Frontmatter) Delete(key string) {
+	if f.format == TOML {
+		if i := f.tomlIndex(key); i >= 0 {
+			f.fields = append(f.fields[:i], f.fields[i+1:]...)
+		}
+		return
+	}
+	if i := f.yamlIndex(key); i >= 0 {
+		f.node.Content = append(f.node.Content[:i], f.node.Content[i+2:]...)
+	}
+}
+
+// Encode writes the block with its delimiters, ending in a newline
+func (f *Frontmatter) Encode() (string, error) {
+	var inner string
+	var err error
+	if f.format == TOML {
+		inner, err = f.encodeTOML()
+	} else {
+		inner, err = f.encodeYAML()
+	}
+	if err != nil {
+		return "", err
+	}
+	delim := f.format.delimiter()
+	return delim + "\n" + inner + delim + "\n", nil
+}
+
+// encodeYAML writes the mapping with two-space indents
+func (f *Frontmatter) encodeYAML() (string, error) {
+	if len(f.node.Content) == 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(f.node); err != nil {
+		return "", fmt.Errorf("frontmatter: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("frontmatter: %w", err)
+	}
+	return b.String(), nil
+}
+
+// yamlIndex returns the position of key in the mapping, or -1
+func (f *Frontmatter) yamlIndex(key string) int {
+	for i := 0; i+1 < len(f.node.Content); i += 2 {
+		if f.node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,115 @@
+package frontmatter
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// rewrite parses content, applies change and parses the result again
+func rewrite(t *testing.T, content string, change func(*Frontmatter)) (string, *Document) {
+	t.Helper()
+	doc, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if doc.Meta == nil {
+		t.Fatal("Parse: no frontmatter")
+	}
+	if change != nil {
+		change(doc.Meta)
+	}
+	out, err := doc.String()
+	if err != nil {
+		t.Fatalf("String: %v", err)
+	}
+	again, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse of rewritten frontmatter: %v\n%s", err, out)
+	}
+	return out, again
+}
+
+// tomlGet returns the decoded value of a top-level TOML key
+func tomlGet(f *Frontmatter, key string) interface{} {
+	if i := f.tomlIndex(key); i >= 0 {
+		return f.fields[i].Value
+	}
+	return nil
+}
+
+func TestTOMLRoundTripKeepsKeysOutOfTables(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"array of tables", "+++\nlinks = [{a = 1}, {a = 2}]\ntitle = \"x\"\n+++\nbody\n"},
+		{"array of tables header", "+++\n[[links]]\na = 1\n\n[[links]]\na = 2\n+++\nbody\n"},
+		{"table", "+++\n[extra]\nsource = \"web\"\n+++\nbody\n"},
+		{"nested table", "+++\n[extra]\nsource = \"web\"\n[extra.meta]\ndepth = 2\n+++\nbody\n"},
+		{"table before key", "+++\nextra = {source = \"web\"}\ntitle = \"x\"\n+++\nbody\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, doc := rewrite(t, tt.content, func(meta *Frontmatter) {
+				if err := meta.Set("title", "New title"); err != nil {
+					t.Fatal(err)
+				}
+				if err := meta.Set("tags", []string{"work", "planning"}); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if got := doc.Meta.Get("title"); got != "New title" {
+				t.Errorf("title = %q, want %q\n%s", got, "New title", out)
+			}
+			if got := doc.Meta.Strings("tags"); !slices.Equal(got, []string{"work", "planning"}) {
+				t.Errorf("tags = %q\n%s", got, out)
+			}
+			if doc.Body != "body\n" {
+				t.Errorf("body = %q", doc.Body)
+			}
+		})
+	}
+}
+
+func TestTOMLRoundTripKeepsTables(t *testing.T) {
+	content := "+++\ntitle = \"x\"\n[[links]]\nurl = \"a\"\n\n[[links]]\nurl = \"b\"\n\n[extra]\nsource = \"web\"\n[extra.meta]\ndepth = 2\n+++\n"
+	out, doc := rewrite(t, content, func(meta *Frontmatter) {
+		if err := meta.Set("tags", []string{"work"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	links, ok := tomlGet(doc.Meta, "links").([]map[string]interface{})
+	if !ok || len(links) != 2 || links[0]["url"] != "a" || links[1]["url"] != "b" {
+		t.Errorf("links = %#v\n%s", tomlGet(doc.Meta, "links"), out)
+	}
+	extra, ok := tomlGet(doc.Meta, "extra").(map[string]interface{})
+	if !ok || extra["source"] != "web" {
+		t.Fatalf("extra = %#v\n%s", tomlGet(doc.Meta, "extra"), out)
+	}
+	if meta, ok := extra["meta"].(map[string]interface{}); !ok || meta["depth"] != int64(2) {
+		t.Errorf("extra.meta = %#v\n%s", extra["meta"], out)
+	}
+	if strings.Index(out, "tags") > strings.Index(out, "[") {
+		t.Errorf("tags written after a table:\n%s", out)
+	}
+}
+
+func TestYAMLRoundTripKeepsOrderAndComments(t *testing.T) {
+	content := "---\n# about this note\ntitle: Old\nlinks:\n  - url: a\nauthor: me\n---\nbody\n"
+	out, doc := rewrite(t, content, func(meta *Frontmatter) {
+		if err := meta.Set("title", "New: title"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if got := doc.Meta.Get("title"); got != "New: title" {
+		t.Errorf("title = %q\n%s", got, out)
+	}
+	if got := doc.Meta.Keys(); !slices.Equal(got, []string{"title", "links", "author"}) {
+		t.Errorf("keys = %q", got)
+	}
+	if !strings.Contains(out, "# about this note") {
+		t.Errorf("comment lost:\n%s", out)
+	}
+}
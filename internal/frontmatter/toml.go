@@ -0,0 +1,142 @@
+package frontmatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlField is one top-level key of a TOML block
+type tomlField struct {
+	Key   string
+	Value interface{}
+}
+
+// localDate is the location BurntSushi/toml gives bare dates, which it
+// writes back as dates; there is no exported way to name it
+var localDate = func() *time.Location {
+	probe := make(map[string]interface{})
+	if _, err := toml.Decode("d = 2006-01-02", &probe); err != nil {
+		return time.UTC
+	}
+	if d, ok := probe["d"].(time.Time); ok {
+		return d.Location()
+	}
+	return time.UTC
+}()
+
+// tomlDate turns t into a bare TOML date
+func tomlDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, localDate)
+}
+
+// parseTOML reads a TOML block, keeping its top-level keys in order
+func parseTOML(block string) (*Frontmatter, error) {
+	values := make(map[string]interface{})
+	meta, err := toml.Decode(block, &values)
+	if err != nil {
+		return nil, fmt.Errorf("frontmatter: %w", err)
+	}
+
+	f := New(TOML)
+	for _, key := range meta.Keys() {
+		// Each [[array]] block lists its key again
+		if len(key) != 1 || f.tomlIndex(key[0]) >= 0 {
+			continue
+		}
+		f.fields = append(f.fields, tomlField{Key: key[0], Value: values[key[0]]})
+	}
+	return f, nil
+}
+
+// setTOML replaces a value in place or adds it at the end
+func (f *Frontmatter) setTOML(key string, value interface{}) {
+	if i := f.tomlIndex(key); i >= 0 {
+		f.fields[i].Value = value
+		return
+	}
+	f.fields = append(f.fields, tomlField{Key: key, Value: value})
+}
+
+// encodeTOML writes the keys in order. Any key after a [table] or
+// [[array]] header would be read back as part of it, so everything the
+// encoder writes as a table, including arrays of tables, goes last.
+func (f *Frontmatter) encodeTOML() (string, error) {
+	var plain, tables strings.Builder
+	for _, field := range f.fields {
+		var b strings.Builder
+		if err := toml.NewEncoder(&b).Encode(map[string]interface{}{field.Key: field.Value}); err != nil {
+			return "", fmt.Errorf("frontmatter: %s: %w", field.Key, err)
+		}
+		if strings.HasPrefix(strings.TrimLeft(b.String(), "\n"), "[") {
+			tables.WriteString(b.String())
+		} else {
+			plain.WriteString(b.String())
+		}
+	}
+	return plain.String() + tables.String(), nil
+}
+
+// tomlValue converts typed text to the type of the value it replaces, when
+// it can be read that way, and to a string otherwise
+func tomlValue(text string, old interface{}) interface{} {
+	switch old.(type) {
+	case int64:
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n
+		}
+	case float64:
+		if n, err := strconv.ParseFloat(text, 64); err == nil {
+			return n
+		}
+	case bool:
+		if b, err := strconv.ParseBool(text); err == nil {
+			return b
+		}
+	case time.Time:
+		if t, err := time.Parse("2006-01-02", text); err == nil {
+			return tomlDate(t)
+		}
+		if t, err := time.Parse(time.RFC3339, text); err == nil {
+			return t
+		}
+	}
+	return text
+}
+
+// tomlIndex returns the position of key, or -1
+func (f *Frontmatter) tomlIndex(key string) int {
+	for i, field := range f.fields {
+		if field.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// tomlText formats a TOML value as text
+func tomlText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.Location() == localDate {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, tomlText(item))
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
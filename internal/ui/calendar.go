@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CalendarView shows the month around the selected day as a grid of weeks
+// starting on Monday, marking the days that have a note
+type CalendarView struct {
+	Selected time.Time // day under the cursor; its month is shown
+	Today    time.Time
+	Marked   map[string]bool // days with a note, as YYYY-MM-DD
+	Style    CalendarStyle
+}
+
+// CalendarStyle styles the calendar grid
+type CalendarStyle struct {
+	Weekday lipgloss.Style // day name row
+	Day     lipgloss.Style
+	Note    lipgloss.Style // a day with a note
+	Today   lipgloss.Style
+	Cursor  lipgloss.Style
+}
+
+// View renders the month heading, the day names and one row per week
+func (c CalendarView) View() string {
+	first := time.Date(c.Selected.Year(), c.Selected.Month(), 1, 0, 0, 0, 0, c.Selected.Location())
+	days := first.AddDate(0, 1, -1).Day()
+	// Blank cells before the 1st, Monday first
+	offset := (int(first.Weekday()) + 6) % 7
+
+	var b strings.Builder
+	heading := first.Format("January 2006")
+	b.WriteString(strings.Repeat(" ", max(0, (7*4-1-len(heading))/2)) + heading + "\n\n")
+	b.WriteString(c.Style.Weekday.Render(" Mo  Tu  We  Th  Fr  Sa  Su") + "\n")
+
+	today := c.Today.Format("2006-01-02")
+	selected := c.Selected.Format("2006-01-02")
+	b.WriteString(strings.Repeat("    ", offset))
+	for day := 1; day <= days; day++ {
+		date := first.AddDate(0, 0, day-1).Format("2006-01-02")
+		style := c.Style.Day
+		if c.Marked[date] {
+			style = c.Style.Note
+		}
+		if date == today {
+			style = c.Style.Today.Inherit(style)
+		}
+		if date == selected {
+			style = c.Style.Cursor.Inherit(style)
+		}
+
+		// Days with a note carry a dot so they stand out without color
+		cell := fmt.Sprintf("%2d", day)
+		if c.Marked[date] {
+			cell += "•"
+		} else {
+			cell += " "
+		}
+		b.WriteString(" " + style.Render(cell))
+
+		if (offset+day)%7 == 0 && day < days {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // Component interface for all UI components
@@ -18,6 +19,10 @@ type Component interface {
 // ListView component for file browsing
 type ListView struct {
 	Items        []string
+	Details      [][]string // optional lines shown under each item (e.g. search snippets)
+	Matches      [][]int    // optional rune positions to highlight in each item
+	Headers      []string   // optional heading shown above an item that starts a group
+	Highlight    string     // text to highlight inside details
 	Cursor       int
 	Width        int
 	Height       int
@@ -30,12 +35,19 @@ type ListStyle struct {
 	Cursor      lipgloss.Style
 	Item        lipgloss.Style
 	EmptyMsg    lipgloss.Style
+	Snippet     lipgloss.Style
+	Match       lipgloss.Style
+	Header      lipgloss.Style
 }
 
 func (l ListView) View() string {
 	if len(l.Items) == 0 {
 		return l.Style.EmptyMsg.Render(l.EmptyMessage)
 	}
+	
+	if len(l.Details) > 0 || len(l.Headers) > 0 {
+		return l.viewWithDetails()
+	}
 
 	var content strings.Builder
 	maxVisible := l.Height
@@ -47,24 +59,7 @@ func (l ListView) View() string {
 	}
 
 	for i := startIdx; i < len(l.Items) && i < startIdx+maxVisible; i++ {
-		cursor := "  "
-		if l.ShowCursor && l.Cursor == i {
-			cursor = "> "
-		}
-
-		item := l.Items[i]
-		// Truncate if too long
-		maxLen := l.Width - 3
-		if len(item) > maxLen && maxLen > 3 {
-			item = item[:maxLen-3] + "..."
-		}
-
-		line := fmt.Sprintf("%s%s", cursor, item)
-		if l.ShowCursor && l.Cursor == i {
-			content.WriteString(l.Style.Cursor.Render(line))
-		} else {
-			content.WriteString(l.Style.Item.Render(line))
-		}
+		content.WriteString(l.renderItem(i))
 		
 		if i < len(l.Items)-1 && i < startIdx+maxVisible-1 {
 			content.WriteString("\n")
@@ -83,6 +78,124 @@ func (l ListView) View() string {
 	return content.String()
 }
 
+// renderItem renders a single item line with its cursor marker and any
+// highlighted match positions
+func (l ListView) renderItem(i int) string {
+	cursor := "  "
+	style := l.Style.Item
+	if l.ShowCursor && l.Cursor == i {
+		cursor = "> "
+		style = l.Style.Cursor
+	}
+
+	item := l.Items[i]
+	// Truncate if too long
+	maxLen := l.Width - 3
+	truncated := false
+	if len(item) > maxLen && maxLen > 3 {
+		item = item[:maxLen-3]
+		truncated = true
+	}
+
+	var positions []int
+	if i < len(l.Matches) {
+		positions = l.Matches[i]
+	}
+	if len(positions) == 0 {
+		if truncated {
+			item += "..."
+		}
+		return style.Render(fmt.Sprintf("%s%s", cursor, item))
+	}
+
+	// Style matched runes individually, grouping runs to keep output small
+	matched := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		matched[pos] = true
+	}
+	matchStyle := l.Style.Match.Inherit(style)
+
+	var b strings.Builder
+	b.WriteString(style.Render(cursor))
+	var run []rune
+	runMatched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(matchStyle.Render(string(run)))
+		} else {
+			b.WriteString(style.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for j, r := range []rune(item) {
+		if matched[j] != runMatched {
+			flush()
+			runMatched = matched[j]
+		}
+		run = append(run, r)
+	}
+	flush()
+	if truncated {
+		b.WriteString(style.Render("..."))
+	}
+	return b.String()
+}
+
+// viewWithDetails renders items that may span several lines each
+func (l ListView) viewWithDetails() string {
+	itemLines := func(i int) int {
+		lines := 1
+		if i < len(l.Details) {
+			lines += len(l.Details[i])
+		}
+		if i < len(l.Headers) && l.Headers[i] != "" {
+			lines++
+		}
+		return lines
+	}
+	
+	// Scroll so the whole cursor item fits in the viewport
+	startIdx := 0
+	used := 0
+	for i := 0; i <= l.Cursor && i < len(l.Items); i++ {
+		used += itemLines(i)
+	}
+	for used > l.Height && startIdx < l.Cursor {
+		used -= itemLines(startIdx)
+		startIdx++
+	}
+	
+	var lines []string
+	i := startIdx
+	for ; i < len(l.Items); i++ {
+		if len(lines) > 0 && len(lines)+itemLines(i) > l.Height {
+			break
+		}
+		
+		if i < len(l.Headers) && l.Headers[i] != "" {
+			lines = append(lines, l.Style.Header.Render(l.Headers[i]))
+		}
+		lines = append(lines, l.renderItem(i))
+		
+		if i < len(l.Details) {
+			for _, detail := range l.Details[i] {
+				snippet := TruncateText(detail, l.Width-7)
+				lines = append(lines, "    "+HighlightMatches(snippet, l.Highlight, l.Style.Snippet, l.Style.Match))
+			}
+		}
+	}
+	
+	// Add scroll indicator
+	if remaining := len(l.Items) - i; remaining > 0 {
+		lines = append(lines, l.Style.EmptyMsg.Render(fmt.Sprintf("... %d more items", remaining)))
+	}
+	
+	return strings.Join(lines, "\n")
+}
+
 // InputModal component for various input modes
 type InputModal struct {
 	Title       string
@@ -126,6 +239,7 @@ func (m InputModal) View() string {
 // Header component
 type Header struct {
 	Title      string
+	ViewName   string
 	FileCount  int
 	Filters    []string
 	SortInfo   string
@@ -141,10 +255,13 @@ type HeaderStyle struct {
 
 func (h Header) View() string {
 	title := fmt.Sprintf("%s (%d files)", h.Title, h.FileCount)
+	if h.ViewName != "" {
+		title = fmt.Sprintf("%s › %s (%d files)", h.Title, h.ViewName, h.FileCount)
+	}
 	
-	// Add active filters
+	// Add active filters as chips, in the order they were applied
 	for _, filter := range h.Filters {
-		title += fmt.Sprintf(" - %s", h.Style.Filter.Render("["+filter+"]"))
+		title += " " + h.Style.Filter.Render("["+filter+" ×]")
 	}
 	
 	// Add sort info
@@ -229,16 +346,13 @@ func (p PreviewPopover) View() string {
 	lines := strings.Split(p.Content, "\n")
 	visibleLines := lines
 	
-	if len(lines) > contentHeight {
+	if contentHeight > 0 && len(lines) > contentHeight {
 		// Apply scrolling
-		end := p.ScrollPos + contentHeight
-		if end > len(lines) {
-			end = len(lines)
-		}
-		visibleLines = lines[p.ScrollPos:end]
+		scroll := max(0, min(p.ScrollPos, len(lines)-contentHeight))
+		visibleLines = lines[scroll : scroll+contentHeight]
 		
 		// Add scroll indicator
-		scrollInfo := fmt.Sprintf(" (line %d/%d)", p.ScrollPos+1, len(lines))
+		scrollInfo := fmt.Sprintf(" (line %d/%d)", scroll+1, len(lines))
 		header += p.Style.Title.Foreground(lipgloss.Color("240")).Render(scrollInfo)
 	}
 	
@@ -246,7 +360,7 @@ func (p PreviewPopover) View() string {
 	content := strings.Join(visibleLines, "\n")
 	
 	// Footer
-	footer := p.Style.Help.Render("[Esc] close  [↑↓/jk] scroll  [e] edit")
+	footer := p.Style.Help.Render("[Esc] close  [jk] scroll  [Tab] link  [Enter] follow  [⌫] back  [e] edit")
 	
 	// Combine all parts
 	fullContent := lipgloss.JoinVertical(
@@ -262,6 +376,38 @@ func (p PreviewPopover) View() string {
 		Render(fullContent)
 }
 
+// PreviewPane shows a note beside the file list in the split layout
+type PreviewPane struct {
+	Title   string
+	Content string
+	Width   int
+	Height  int
+	Style   PopoverStyle
+}
+
+func (p PreviewPane) View() string {
+	// Border takes two columns and rows, padding one column each side
+	innerWidth := p.Width - 4
+	innerHeight := p.Height - 2
+	if innerWidth < 1 || innerHeight < 1 {
+		return ""
+	}
+	
+	lines := []string{p.Style.Title.UnsetMarginBottom().Render(ansi.Truncate(p.Title, innerWidth, "…")), ""}
+	for _, line := range strings.Split(p.Content, "\n") {
+		if len(lines) >= innerHeight {
+			break
+		}
+		lines = append(lines, ansi.Truncate(line, innerWidth, "…"))
+	}
+	
+	return p.Style.Border.
+		Padding(0, 1).
+		Width(p.Width - 2).
+		Height(innerHeight).
+		Render(strings.Join(lines, "\n"))
+}
+
 // ConfirmDialog component
 type ConfirmDialog struct {
 	Title    string
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -28,6 +29,16 @@ type ModelIntegration struct {
 	PreviewMode     bool
 	DeleteMode      bool
 	SortMode        bool
+	ViewMode        bool
+	BacklinksMode   bool
+	TaskMode        bool
+	TaskTagMode     bool
+	TemplateMode    bool
+	CalendarMode    bool
+	PeriodMode      bool
+	MetaMode        bool
+	SyncMode        bool
+	TrashMode       bool
 	OldMode         bool
 	RenameMode      bool
 
@@ -37,6 +48,14 @@ type ModelIntegration struct {
 	TagInput        textinput.Model
 	TagCreateInput  textinput.Model
 	OldInput        textinput.Model
+	TaskTagInput    textinput.Model
+	MetaInput       textinput.Model
+
+	// Search state
+	ContentSearch  bool
+	SnippetQuery   string              // query the snippets were matched with
+	Snippets       map[string][]string // matching lines keyed by file path
+	Highlights     map[string][]int    // matched rune positions keyed by file path
 
 	// Preview state
 	PreviewContent string
@@ -49,21 +68,76 @@ type ModelIntegration struct {
 	PendingTitle   string
 	CurrentSort    string
 	ReversedSort   bool
-	OldDays        int
-	TextFilter     bool
-	TagFilter      bool
-	TaskFilter     bool
-	DailyFilter    bool
-	OldFilter      bool
+	Filters        []string // labels of the active filters, in order
+	ActiveView     string   // name of the saved view in use
+	Views          []string // saved view names, while picking
+	ViewSummaries  []string // filters of each saved view
+	ViewCursor     int
+
+	// Backlinks panel
+	BacklinkTarget   string              // note whose backlinks are shown
+	BacklinkSources  []string            // linking notes
+	BacklinkContexts map[string][]string // linking lines keyed by source
+	BacklinkCursor   int
+
+	// Periodic notes, in order, with the keys that open them
+	Periods        []PeriodKey
+
+	// Daily note calendar
+	CalendarDay    time.Time       // day under the cursor
+	CalendarNotes  map[string]bool // days with a daily note, as YYYY-MM-DD
+
+	// Denote sync dialog
+	SyncFile        string
+	SyncDiff        []string // disagreements, then what each choice changes
+
+	// Trash browser
+	TrashItems      []string // where each trashed note was deleted from
+	TrashDetails    []string // when each was deleted
+	TrashCursor     int
+	TrashPurge      bool     // is purging the highlighted note being confirmed?
+
+	// Metadata editor
+	MetaFile        string
+	MetaRows        []string // "key: value" lines, then "#tag" lines
+	MetaHeaders     []string // group heading above a row, if any
+	MetaCursor      int
+	MetaEdit        string   // what the input is for, or "" when closed
+	MetaSuggestions []string // known tags matching the tag being typed
+
+	// Template picker
+	Templates      []string // template names, after the blank note choice
+	TemplateCursor int
+
+	// Task view
+	TaskItems    []string // checkbox and text of each task
+	TaskFiles    []string // note each task is in
+	TaskLines    []int    // line of each task in its note
+	TaskMeta     []string // due date and priority of each task
+	TaskHeaders  []string // agenda section starting at each task, if any
+	TaskAgenda   bool
+	TaskSort     string
+	TaskCursor   int
+	TaskTag      string // tag the tasks are limited to
+	TaskShowDone bool
 
 	// Status message
 	StatusMsg      StatusMessage
 	StatusDuration int // frames remaining
 
+	// Split layout preview
+	SplitPreview     string // rendered note under the cursor
+	SplitPreviewFile string
+
 	// Configuration
 	ShowTitles         bool
 	DenoteFilenames    bool
 	ThemeName          string
+	SplitPane          string  // "", "vertical" or "horizontal"
+	SplitRatio         float64 // fraction of the width or height for the list
+
+	// DisplayName resolves a file path to its list label (e.g. indexed title)
+	DisplayName        func(path string) string
 
 	// UI Components
 	theme    Theme
@@ -75,9 +149,24 @@ type ModelIntegration struct {
 func (m *ModelIntegration) Initialize() {
 	m.theme = GetTheme(m.ThemeName)
 	m.layout = NewLayout(m.Width, m.Height, m.theme)
+	m.layout.Split = ParseSplitOrientation(m.SplitPane)
+	m.layout.SplitRatio = m.SplitRatio
 	m.createComposer()
 }
 
+// PreviewPaneWidth returns the width available to notes rendered for the
+// split layout's preview pane, or 0 when the layout isn't split
+func (m *ModelIntegration) PreviewPaneWidth() int {
+	if m.layout == nil {
+		m.Initialize()
+	}
+	_, preview := m.layout.Panes()
+	if preview.Width <= 4 {
+		return 0
+	}
+	return preview.Width - 4
+}
+
 // UpdateSize updates the UI dimensions
 func (m *ModelIntegration) UpdateSize(width, height int) {
 	m.Width = width
@@ -153,6 +242,8 @@ func (m *ModelIntegration) createComposer() {
 	m.composer.SetInput("tag", m.TagInput)
 	m.composer.SetInput("tagcreate", m.TagCreateInput)
 	m.composer.SetInput("old", m.OldInput)
+	m.composer.SetInput("tasktag", m.TaskTagInput)
+	m.composer.SetInput("meta", m.MetaInput)
 }
 
 // updateComposerState updates the composer with current state
@@ -165,6 +256,8 @@ func (m *ModelIntegration) updateComposerState() {
 	m.composer.SetInput("tag", m.TagInput)
 	m.composer.SetInput("tagcreate", m.TagCreateInput)
 	m.composer.SetInput("old", m.OldInput)
+	m.composer.SetInput("tasktag", m.TaskTagInput)
+	m.composer.SetInput("meta", m.MetaInput)
 }
 
 // createViewState converts model state to view state
@@ -175,6 +268,48 @@ func (m *ModelIntegration) createViewState() ViewState {
 		displayFiles[i] = m.getEnhancedDisplayName(file)
 	}
 
+	// Line up content search snippets with the filtered files
+	var details [][]string
+	if len(m.Snippets) > 0 {
+		details = make([][]string, len(m.Filtered))
+		for i, file := range m.Filtered {
+			details[i] = m.Snippets[file]
+		}
+	}
+
+	// Line up fuzzy match positions with the filtered files
+	var matches [][]int
+	if len(m.Highlights) > 0 {
+		matches = make([][]int, len(m.Filtered))
+		for i, file := range m.Filtered {
+			matches[i] = m.Highlights[file]
+		}
+	}
+
+	// Name the linking notes and line up their context lines
+	backlinks := make([]string, len(m.BacklinkSources))
+	contexts := make([][]string, len(m.BacklinkSources))
+	for i, source := range m.BacklinkSources {
+		backlinks[i] = m.getEnhancedDisplayName(source)
+		contexts[i] = m.BacklinkContexts[source]
+	}
+
+	// Say where each task lives
+	taskDetails := make([][]string, len(m.TaskFiles))
+	for i, file := range m.TaskFiles {
+		detail := fmt.Sprintf("%s:%d", m.getEnhancedDisplayName(file), m.TaskLines[i])
+		if i < len(m.TaskMeta) && m.TaskMeta[i] != "" {
+			detail += "  " + m.TaskMeta[i]
+		}
+		taskDetails[i] = []string{detail}
+	}
+
+	// Say when each trashed note was deleted
+	trashDetails := make([][]string, len(m.TrashDetails))
+	for i, detail := range m.TrashDetails {
+		trashDetails[i] = []string{detail}
+	}
+
 	return ViewState{
 		Mode:           m.getCurrentMode(),
 		Files:          displayFiles,
@@ -186,18 +321,62 @@ func (m *ModelIntegration) createViewState() ViewState {
 		Layout:         m.layout,
 		
 		SearchQuery:    m.Search.Value(),
+		ContentSearch:  m.ContentSearch,
+		SnippetQuery:   m.SnippetQuery,
+		Details:        details,
+		Matches:        matches,
 		SelectedFile:   m.getEnhancedDisplayName(m.PreviewFile),
 		PreviewContent: m.PreviewContent,
 		PreviewScroll:  m.PreviewScroll,
+		SplitPreview:   m.SplitPreview,
+		SplitTitle:     m.getEnhancedDisplayName(m.SplitPreviewFile),
 		DeleteTarget:   m.getEnhancedDisplayName(m.DeleteFile),
 		StatusMessage:  m.StatusMsg,
 		
-		TaskFilter:     m.TaskFilter,
-		TagFilter:      m.TagFilter,
-		TextFilter:     m.TextFilter,
-		DailyFilter:    m.DailyFilter,
-		OldFilter:      m.OldFilter,
-		OldDays:        m.OldDays,
+		Filters:        m.Filters,
+		ActiveView:     m.ActiveView,
+		Views:          m.Views,
+		ViewSummaries:  m.ViewSummaries,
+		ViewCursor:     m.ViewCursor,
+		
+		BacklinkTarget:   m.getEnhancedDisplayName(m.BacklinkTarget),
+		Backlinks:        backlinks,
+		BacklinkContexts: contexts,
+		BacklinkCursor:   m.BacklinkCursor,
+		
+		Tasks:          m.TaskItems,
+		TaskDetails:    taskDetails,
+		TaskHeaders:    m.TaskHeaders,
+		TaskAgenda:     m.TaskAgenda,
+		TaskSort:       m.TaskSort,
+		TaskCursor:     m.TaskCursor,
+		TaskTag:        m.TaskTag,
+		TaskShowDone:   m.TaskShowDone,
+		TaskTagMode:    m.TaskTagMode,
+		
+		Periods:        m.Periods,
+		
+		CalendarDay:    m.CalendarDay,
+		CalendarNotes:  m.CalendarNotes,
+		
+		SyncTarget:      m.getEnhancedDisplayName(m.SyncFile),
+		SyncDiff:        m.SyncDiff,
+		
+		TrashItems:      m.TrashItems,
+		TrashDetails:    trashDetails,
+		TrashCursor:     m.TrashCursor,
+		TrashPurge:      m.TrashPurge,
+		
+		MetaTarget:      m.getEnhancedDisplayName(m.MetaFile),
+		MetaRows:        m.MetaRows,
+		MetaHeaders:     m.MetaHeaders,
+		MetaCursor:      m.MetaCursor,
+		MetaEdit:        m.MetaEdit,
+		MetaSuggestions: m.MetaSuggestions,
+		
+		PendingTitle:   m.PendingTitle,
+		Templates:      m.Templates,
+		TemplateCursor: m.TemplateCursor,
 		
 		CurrentSort:    m.CurrentSort,
 		ReversedSort:   m.ReversedSort,
@@ -215,6 +394,33 @@ func (m *ModelIntegration) getCurrentMode() ViewMode {
 	if m.SortMode {
 		return ModeSort
 	}
+	if m.ViewMode {
+		return ModeViewPicker
+	}
+	if m.BacklinksMode {
+		return ModeBacklinks
+	}
+	if m.TaskMode {
+		return ModeTasks
+	}
+	if m.TemplateMode {
+		return ModeTemplatePicker
+	}
+	if m.CalendarMode {
+		return ModeCalendar
+	}
+	if m.PeriodMode {
+		return ModePeriodMenu
+	}
+	if m.MetaMode {
+		return ModeMetadata
+	}
+	if m.SyncMode {
+		return ModeSync
+	}
+	if m.TrashMode {
+		return ModeTrash
+	}
 	if m.SearchMode {
 		return ModeSearch
 	}
@@ -239,6 +445,11 @@ func (m *ModelIntegration) getEnhancedDisplayName(fullPath string) string {
 		return ""
 	}
 
+	// Prefer the application's resolver when one is provided
+	if m.DisplayName != nil {
+		return m.DisplayName(fullPath)
+	}
+
 	// Get relative path
 	rel, err := filepath.Rel(m.CWD, fullPath)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // Layout manages the application's layout
@@ -12,6 +13,35 @@ type Layout struct {
 	Height        int
 	MarginPercent int // Percentage of width for margins
 	Theme         Theme
+	Split         SplitOrientation // how the file list and preview share the screen
+	SplitRatio    float64          // fraction of the content area given to the file list
+}
+
+// SplitOrientation selects the two-pane arrangement
+type SplitOrientation int
+
+const (
+	SplitNone       SplitOrientation = iota // file list only
+	SplitVertical                           // list on the left, preview on the right
+	SplitHorizontal                         // list on top, preview below
+)
+
+// ParseSplitOrientation maps the split_pane config value to an orientation
+func ParseSplitOrientation(name string) SplitOrientation {
+	switch strings.ToLower(name) {
+	case "vertical", "right", "side":
+		return SplitVertical
+	case "horizontal", "bottom", "stacked":
+		return SplitHorizontal
+	default:
+		return SplitNone
+	}
+}
+
+// PaneSize is the space available to one pane
+type PaneSize struct {
+	Width  int
+	Height int
 }
 
 // NewLayout creates a new layout manager
@@ -26,15 +56,63 @@ func NewLayout(width, height int, theme Theme) *Layout {
 
 // ContentArea returns the available content dimensions
 func (l *Layout) ContentArea() (width, height int) {
-	marginSize := l.Width * l.MarginPercent / 100
+	marginSize := l.marginSize()
 	width = l.Width - (marginSize * 2)
 	height = l.Height
 	return
 }
 
+// marginSize is the blank space on each side of the content; split panes
+// need the width, so they get a narrow margin
+func (l *Layout) marginSize() int {
+	if l.Split != SplitNone {
+		return l.Width * 2 / 100
+	}
+	return l.Width * l.MarginPercent / 100
+}
+
+// Panes returns the sizes of the file list and preview panes. Without a
+// split the list gets the whole content area and the preview nothing.
+func (l *Layout) Panes() (list, preview PaneSize) {
+	width, height := l.ContentArea()
+	height -= 6 // Reserve space for header/footer
+	
+	ratio := l.SplitRatio
+	if ratio <= 0.1 || ratio >= 0.9 {
+		ratio = 0.4
+	}
+	
+	switch l.Split {
+	case SplitVertical:
+		list = PaneSize{Width: int(float64(width) * ratio), Height: height}
+		preview = PaneSize{Width: width - list.Width - 1, Height: height}
+	case SplitHorizontal:
+		list = PaneSize{Width: width, Height: int(float64(height) * ratio)}
+		preview = PaneSize{Width: width, Height: height - list.Height - 1}
+	default:
+		list = PaneSize{Width: width, Height: height}
+	}
+	return list, preview
+}
+
+// JoinPanes places the file list and preview side by side or stacked
+func (l *Layout) JoinPanes(list, preview string) string {
+	listSize, _ := l.Panes()
+	list = lipgloss.NewStyle().
+		Width(listSize.Width).
+		Height(listSize.Height).
+		MaxHeight(listSize.Height).
+		Render(list)
+	
+	if l.Split == SplitHorizontal {
+		return lipgloss.JoinVertical(lipgloss.Left, list, "", preview)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, " ", preview)
+}
+
 // ApplyMargins wraps content with appropriate margins
 func (l *Layout) ApplyMargins(content string) string {
-	marginSize := l.Width * l.MarginPercent / 100
+	marginSize := l.marginSize()
 	contentWidth, _ := l.ContentArea()
 	
 	style := lipgloss.NewStyle().
@@ -124,7 +202,43 @@ func TruncateText(text string, maxLen int) string {
 	return text[:maxLen-3] + "..."
 }
 
-// WrapText wraps text to fit within width
+// HighlightMatches renders text with every case-insensitive occurrence of
+// query in the match style and the remainder in the base style
+func HighlightMatches(text, query string, base, match lipgloss.Style) string {
+	if query == "" {
+		return base.Render(text)
+	}
+	
+	lowerText := strings.ToLower(text)
+	lowerQuery := strings.ToLower(query)
+	if len(lowerText) != len(text) {
+		// Case folding changed byte offsets; don't risk slicing mid-rune
+		return base.Render(text)
+	}
+	
+	var b strings.Builder
+	pos := 0
+	for {
+		idx := strings.Index(lowerText[pos:], lowerQuery)
+		if idx < 0 {
+			break
+		}
+		start := pos + idx
+		end := start + len(lowerQuery)
+		if start > pos {
+			b.WriteString(base.Render(text[pos:start]))
+		}
+		b.WriteString(match.Render(text[start:end]))
+		pos = end
+	}
+	if pos < len(text) {
+		b.WriteString(base.Render(text[pos:]))
+	}
+	return b.String()
+}
+
+// WrapText wraps text to fit within width, measuring words by their
+// display width so styled and wide text wraps correctly
 func WrapText(text string, width int) []string {
 	if width <= 0 {
 		return []string{text}
@@ -140,7 +254,19 @@ func WrapText(text string, width int) []string {
 	currentLen := 0
 	
 	for _, word := range words {
-		wordLen := len(word)
+		wordLen := lipgloss.Width(word)
+		if wordLen > width {
+			// A word wider than a whole line is broken across lines,
+			// leaving its last piece to start the next one
+			if currentLen > 0 {
+				lines = append(lines, strings.Join(current, " "))
+			}
+			pieces := strings.Split(ansi.Hardwrap(word, width, true), "\n")
+			lines = append(lines, pieces[:len(pieces)-1]...)
+			word = pieces[len(pieces)-1]
+			wordLen = lipgloss.Width(word)
+			current, currentLen = nil, 0
+		}
 		if currentLen > 0 && currentLen+1+wordLen > width {
 			// Start new line
 			lines = append(lines, strings.Join(current, " "))
@@ -2,135 +2,605 @@ package ui
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pdxmph/notes-tui/internal/frontmatter"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// LinkKind identifies the syntax a note link was written in
+type LinkKind int
+
+const (
+	LinkDenote   LinkKind = iota // [[denote:20250623T093045]]
+	LinkWiki                     // [[Note Title]] or [[Note Title|label]]
+	LinkMarkdown                 // [label](relative/path.md)
+)
+
+// PreviewLink is a link to another note found while rendering
+type PreviewLink struct {
+	Kind   LinkKind
+	Target string // identifier, title or relative path as written
+	Text   string // text shown in the preview
+	Line   int    // rendered line the link appears on
+}
+
+// MarkdownOptions controls how RenderMarkdown draws a note
+type MarkdownOptions struct {
+	Width      int
+	Theme      Theme
+	ActiveLink int                    // index of the highlighted link, or -1
+	IsBroken   func(PreviewLink) bool // reports links whose target is missing
+
+	// NoteTarget returns the file part of a link destination that points
+	// at a local note. Without it, links are shown as plain URLs.
+	NoteTarget func(dest string) (string, bool)
+}
+
+// MarkdownStyle defines how rendered notes look
+type MarkdownStyle struct {
+	Heading1      lipgloss.Style
+	Heading2      lipgloss.Style
+	Heading3      lipgloss.Style // and deeper levels
+	Strong        lipgloss.Style
+	Emphasis      lipgloss.Style
+	Strikethrough lipgloss.Style
+	Code          lipgloss.Style // inline code
+	CodeBlock     lipgloss.Style
+	CodeLanguage  lipgloss.Style // fenced code language label
+	CodeBorder    lipgloss.Style
+	ListMarker    lipgloss.Style
+	TaskOpen      lipgloss.Style
+	TaskDone      lipgloss.Style
+	Quote         lipgloss.Style // the quote bar
+	QuoteText     lipgloss.Style
+	Rule          lipgloss.Style
+	TableHeader   lipgloss.Style
+	TableBorder   lipgloss.Style
+	Link          lipgloss.Style // links to other notes
+	LinkActive    lipgloss.Style
+	LinkBroken    lipgloss.Style
+	URL           lipgloss.Style // links to anything else
+	Image         lipgloss.Style
+}
+
+var (
+	previewWikiLink = regexp.MustCompile(`^\[\[([^\[\]]+)\]\]`)
+
+	// linkMarker tags the first word of a note link so its rendered line can
+	// be found after wrapping. Being an escape sequence, it takes no width.
+	linkMarker = regexp.MustCompile("\x1b\\]notes-link;(\\d+)\x07")
+
+	markdownParser = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(util.Prioritized(wikiLinkParser{}, 199)),
+		),
+	).Parser()
+
+	listBullets = []string{"•", "◦", "▪"}
 )
 
 // RenderSimpleMarkdown renders markdown content for preview
 func RenderSimpleMarkdown(content string, width int) string {
-	lines := strings.Split(content, "\n")
-	var result []string
-	
-	// Skip YAML frontmatter
-	startIdx := 0
-	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
-		// Find the closing ---
-		for i := 1; i < len(lines); i++ {
-			if strings.TrimSpace(lines[i]) == "---" {
-				startIdx = i + 1
-				break
+	rendered, _ := RenderMarkdown(content, MarkdownOptions{Width: width, Theme: DefaultTheme(), ActiveLink: -1})
+	return rendered
+}
+
+// RenderMarkdown renders markdown content for preview and returns the note
+// links it contains, in order, with the active and broken ones styled
+func RenderMarkdown(content string, opts MarkdownOptions) (string, []PreviewLink) {
+	source := []byte(frontmatter.Body(content))
+	doc := markdownParser.Parse(text.NewReader(source))
+
+	width := opts.Width
+	if width <= 0 {
+		width = 80
+	}
+	r := &markdownRenderer{source: source, opts: opts, style: opts.Theme.Markdown}
+	lines := r.blocks(doc, width)
+
+	// Record the line each link landed on, then drop the markers
+	for i, line := range lines {
+		if !strings.Contains(line, "\x1b]notes-link;") {
+			continue
+		}
+		for _, m := range linkMarker.FindAllStringSubmatch(line, -1) {
+			if index, err := strconv.Atoi(m[1]); err == nil && index < len(r.links) {
+				r.links[index].Line = i
 			}
 		}
+		lines[i] = linkMarker.ReplaceAllString(line, "")
 	}
-	
-	// Define styles
-	h1Style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
-	h2Style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("14"))
-	h3Style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
-	codeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
-	bulletStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
-	
-	inCodeBlock := false
-	
-	for i := startIdx; i < len(lines); i++ {
-		line := lines[i]
-		// Handle code blocks
-		if strings.HasPrefix(line, "```") {
-			inCodeBlock = !inCodeBlock
-			if inCodeBlock {
-				result = append(result, codeStyle.Render("─────────────────────"))
-			} else {
-				result = append(result, codeStyle.Render("─────────────────────"))
-			}
+	return strings.Join(lines, "\n"), r.links
+}
+
+// markdownRenderer draws a parsed note as terminal lines
+type markdownRenderer struct {
+	source []byte
+	opts   MarkdownOptions
+	style  MarkdownStyle
+	base   lipgloss.Style // style paragraphs inherit, e.g. inside quotes
+	depth  int            // list nesting
+	links  []PreviewLink
+}
+
+// blocks renders the block children of node, separating them with blank
+// lines unless they belong to a tight list item
+func (r *markdownRenderer) blocks(node ast.Node, width int) []string {
+	tight := false
+	if item, ok := node.(*ast.ListItem); ok {
+		if list, ok := item.Parent().(*ast.List); ok {
+			tight = list.IsTight
+		}
+	}
+
+	var lines []string
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		rendered := r.block(child, width)
+		if len(rendered) == 0 {
 			continue
 		}
-		
-		if inCodeBlock {
-			result = append(result, codeStyle.Render(line))
+		if len(lines) > 0 && !tight {
+			lines = append(lines, "")
+		}
+		lines = append(lines, rendered...)
+	}
+	return lines
+}
+
+// block renders a single block node to lines at most width wide
+func (r *markdownRenderer) block(node ast.Node, width int) []string {
+	switch n := node.(type) {
+	case *ast.Heading:
+		style := r.style.Heading3
+		switch n.Level {
+		case 1:
+			style = r.style.Heading1
+		case 2:
+			style = r.style.Heading2
+		}
+		return r.wrap(r.inlines(n, style), width)
+
+	case *ast.Paragraph, *ast.TextBlock:
+		return r.wrap(r.inlines(n, r.base), width)
+
+	case *ast.ThematicBreak:
+		return []string{r.style.Rule.Render(strings.Repeat("─", width))}
+
+	case *ast.FencedCodeBlock:
+		return r.codeBlock(n, string(n.Language(r.source)), width)
+
+	case *ast.CodeBlock:
+		return r.codeBlock(n, "", width)
+
+	case *ast.Blockquote:
+		saved := r.base
+		r.base = r.style.QuoteText
+		inner := r.blocks(n, width-2)
+		r.base = saved
+
+		bar := r.style.Quote.Render("│")
+		for i, line := range inner {
+			inner[i] = bar + " " + line
+		}
+		return inner
+
+	case *ast.List:
+		return r.list(n, width)
+
+	case *extast.Table:
+		return r.table(n, width)
+
+	case *ast.HTMLBlock:
+		var lines []string
+		for i := 0; i < n.Lines().Len(); i++ {
+			seg := n.Lines().At(i)
+			lines = append(lines, strings.TrimRight(string(seg.Value(r.source)), "\n"))
+		}
+		return lines
+	}
+
+	// Anything else renders whatever blocks it holds
+	return r.blocks(node, width)
+}
+
+// codeBlock renders code verbatim inside a border, labelled with its
+// language when known and highlighted when there is a lexer for it. Long
+// lines are cut rather than wrapped.
+func (r *markdownRenderer) codeBlock(node ast.Node, language string, width int) []string {
+	top := r.style.CodeBorder.Render("╭─")
+	if language != "" {
+		top += " " + r.style.CodeLanguage.Render(language)
+	}
+	lines := []string{top}
+
+	var code strings.Builder
+	for i := 0; i < node.Lines().Len(); i++ {
+		seg := node.Lines().At(i)
+		code.Write(seg.Value(r.source))
+	}
+	source := strings.TrimSuffix(strings.ReplaceAll(code.String(), "\t", "    "), "\n")
+
+	body, ok := highlightCode(source, language, r.opts.Theme.Syntax, r.style.CodeBlock)
+	if !ok {
+		body = nil
+		for _, line := range strings.Split(source, "\n") {
+			body = append(body, r.style.CodeBlock.Render(line))
+		}
+	}
+
+	gutter := r.style.CodeBorder.Render("│") + " "
+	for _, line := range body {
+		lines = append(lines, gutter+ansi.Truncate(line, width-2, "…"))
+	}
+	return append(lines, r.style.CodeBorder.Render("╰─"))
+}
+
+// list renders a bulleted or numbered list, with task items shown as
+// checkboxes and nested lists indented under their item
+func (r *markdownRenderer) list(list *ast.List, width int) []string {
+	bullet := listBullets[r.depth%len(listBullets)]
+	number := list.Start
+	numberWidth := len(strconv.Itoa(list.Start + list.ChildCount() - 1))
+
+	r.depth++
+	defer func() { r.depth-- }()
+
+	var lines []string
+	for child := list.FirstChild(); child != nil; child = child.NextSibling() {
+		item, ok := child.(*ast.ListItem)
+		if !ok {
 			continue
 		}
-		
-		// Handle headers
-		if strings.HasPrefix(line, "# ") {
-			result = append(result, h1Style.Render(strings.TrimPrefix(line, "# ")))
-		} else if strings.HasPrefix(line, "## ") {
-			result = append(result, h2Style.Render(strings.TrimPrefix(line, "## ")))
-		} else if strings.HasPrefix(line, "### ") {
-			result = append(result, h3Style.Render(strings.TrimPrefix(line, "### ")))
-		} else if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
-			// Handle bullet points
-			bullet := bulletStyle.Render("•")
-			content := strings.TrimPrefix(strings.TrimPrefix(line, "- "), "* ")
-			result = append(result, fmt.Sprintf("%s %s", bullet, content))
-		} else if strings.HasPrefix(line, "> ") {
-			// Handle blockquotes
-			quoteLine := strings.TrimPrefix(line, "> ")
-			result = append(result, lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("8")).Render("│ " + quoteLine))
-		} else if strings.TrimSpace(line) == "---" || strings.TrimSpace(line) == "***" {
-			// Handle horizontal rules
-			result = append(result, strings.Repeat("─", width-6))
+
+		var marker string
+		if list.IsOrdered() {
+			marker = r.style.ListMarker.Render(fmt.Sprintf("%*d.", numberWidth, number))
+			number++
 		} else {
-			// Handle inline formatting
-			formatted := line
-			
-			// Bold
-			for strings.Contains(formatted, "**") {
-				start := strings.Index(formatted, "**")
-				if start == -1 {
-					break
-				}
-				end := strings.Index(formatted[start+2:], "**")
-				if end == -1 {
-					break
-				}
-				end += start + 2
-				
-				before := formatted[:start]
-				bold := lipgloss.NewStyle().Bold(true).Render(formatted[start+2:end])
-				after := formatted[end+2:]
-				formatted = before + bold + after
+			marker = r.style.ListMarker.Render(bullet)
+		}
+		if box := taskCheckBox(item); box != nil {
+			check := r.style.TaskOpen.Render("☐")
+			if box.IsChecked {
+				check = r.style.TaskDone.Render("☑")
 			}
-			
-			// Italic
-			for strings.Contains(formatted, "*") && !strings.Contains(formatted, "**") {
-				start := strings.Index(formatted, "*")
-				if start == -1 {
-					break
-				}
-				end := strings.Index(formatted[start+1:], "*")
-				if end == -1 {
-					break
-				}
-				end += start + 1
-				
-				before := formatted[:start]
-				italic := lipgloss.NewStyle().Italic(true).Render(formatted[start+1:end])
-				after := formatted[end+1:]
-				formatted = before + italic + after
+			if list.IsOrdered() {
+				marker += " " + check
+			} else {
+				marker = check
 			}
-			
-			// Inline code
-			for strings.Contains(formatted, "`") {
-				start := strings.Index(formatted, "`")
-				if start == -1 {
-					break
-				}
-				end := strings.Index(formatted[start+1:], "`")
-				if end == -1 {
-					break
+		}
+
+		indent := lipgloss.Width(marker) + 1
+		content := r.blocks(item, width-indent)
+		if len(content) == 0 {
+			content = []string{""}
+		}
+
+		if len(lines) > 0 && !list.IsTight {
+			lines = append(lines, "")
+		}
+		lines = append(lines, marker+" "+content[0])
+		pad := strings.Repeat(" ", indent)
+		for _, line := range content[1:] {
+			if line == "" {
+				lines = append(lines, "")
+			} else {
+				lines = append(lines, pad+line)
+			}
+		}
+	}
+	return lines
+}
+
+// taskCheckBox returns the checkbox starting a task list item, if any
+func taskCheckBox(item *ast.ListItem) *extast.TaskCheckBox {
+	if first := item.FirstChild(); first != nil {
+		if box, ok := first.FirstChild().(*extast.TaskCheckBox); ok {
+			return box
+		}
+	}
+	return nil
+}
+
+// table renders a GFM table, narrowing the widest columns until it fits
+func (r *markdownRenderer) table(table *extast.Table, width int) []string {
+	var rows [][]string
+	for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+		style := r.base
+		if _, ok := row.(*extast.TableHeader); ok {
+			style = r.style.TableHeader
+		}
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, strings.TrimSpace(r.inlines(cell, style)))
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	columns := len(table.Alignments)
+	widths := make([]int, columns)
+	for _, cells := range rows {
+		for i, cell := range cells {
+			if i < columns && lipgloss.Width(cell) > widths[i] {
+				widths[i] = lipgloss.Width(cell)
+			}
+		}
+	}
+
+	// Columns are separated by " │ "
+	total := func() int {
+		sum := 3 * (columns - 1)
+		for _, w := range widths {
+			sum += w
+		}
+		return sum
+	}
+	for total() > width {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+	}
+
+	separator := r.style.TableBorder.Render(" │ ")
+	var lines []string
+	for i, cells := range rows {
+		var parts []string
+		for col := 0; col < columns; col++ {
+			cell := ""
+			if col < len(cells) {
+				cell = ansi.Truncate(cells[col], widths[col], "…")
+			}
+			parts = append(parts, alignCell(cell, widths[col], table.Alignments[col]))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(parts, separator), " "))
+
+		if i == 0 {
+			var rule []string
+			for _, w := range widths {
+				rule = append(rule, strings.Repeat("─", w))
+			}
+			lines = append(lines, r.style.TableBorder.Render(strings.Join(rule, "─┼─")))
+		}
+	}
+	return lines
+}
+
+// alignCell pads a table cell to width according to its column alignment
+func alignCell(cell string, width int, align extast.Alignment) string {
+	gap := width - lipgloss.Width(cell)
+	if gap <= 0 {
+		return cell
+	}
+	switch align {
+	case extast.AlignRight:
+		return strings.Repeat(" ", gap) + cell
+	case extast.AlignCenter:
+		return strings.Repeat(" ", gap/2) + cell + strings.Repeat(" ", gap-gap/2)
+	}
+	return cell + strings.Repeat(" ", gap)
+}
+
+// wrap breaks rendered inline text into lines at most width wide, keeping
+// hard line breaks
+func (r *markdownRenderer) wrap(inline string, width int) []string {
+	var lines []string
+	for _, part := range strings.Split(inline, "\n") {
+		wrapped := WrapText(part, width)
+		if len(wrapped) == 0 {
+			wrapped = []string{""}
+		}
+		lines = append(lines, wrapped...)
+	}
+	return lines
+}
+
+// inlines renders the inline children of node with style as the base.
+// Every word is styled on its own so the result can be wrapped anywhere.
+func (r *markdownRenderer) inlines(node ast.Node, style lipgloss.Style) string {
+	var b strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		r.inline(&b, child, style)
+	}
+	return b.String()
+}
+
+// inline renders one inline node into b
+func (r *markdownRenderer) inline(b *strings.Builder, node ast.Node, style lipgloss.Style) {
+	switch n := node.(type) {
+	case *ast.Text:
+		b.WriteString(styleWords(string(n.Segment.Value(r.source)), style))
+		if n.HardLineBreak() {
+			b.WriteString("\n")
+		} else if n.SoftLineBreak() {
+			b.WriteString(" ")
+		}
+
+	case *ast.String:
+		b.WriteString(styleWords(string(n.Value), style))
+
+	case *ast.Emphasis:
+		emphasis := r.style.Emphasis
+		if n.Level >= 2 {
+			emphasis = r.style.Strong
+		}
+		b.WriteString(r.inlines(n, emphasis.Inherit(style)))
+
+	case *extast.Strikethrough:
+		b.WriteString(r.inlines(n, r.style.Strikethrough.Inherit(style)))
+
+	case *ast.CodeSpan:
+		b.WriteString(styleWords(r.plainText(n), r.style.Code.Inherit(style)))
+
+	case *ast.Link:
+		label := strings.TrimSpace(r.plainText(n))
+		if r.opts.NoteTarget != nil {
+			if target, ok := r.opts.NoteTarget(string(n.Destination)); ok {
+				if label == "" {
+					label = target
 				}
-				end += start + 1
-				
-				before := formatted[:start]
-				code := codeStyle.Render(formatted[start+1:end])
-				after := formatted[end+1:]
-				formatted = before + code + after
+				b.WriteString(r.noteLink(PreviewLink{Kind: LinkMarkdown, Target: target, Text: label}))
+				return
+			}
+		}
+		if label == "" {
+			label = string(n.Destination)
+		}
+		b.WriteString(styleWords(label, r.style.URL.Inherit(style)))
+
+	case *ast.AutoLink:
+		b.WriteString(styleWords(string(n.Label(r.source)), r.style.URL.Inherit(style)))
+
+	case *ast.Image:
+		alt := strings.TrimSpace(r.plainText(n))
+		if alt == "" {
+			alt = "image"
+		}
+		b.WriteString(styleWords("["+alt+"]", r.style.Image.Inherit(style)))
+
+	case *wikiLink:
+		b.WriteString(r.noteLink(n.link))
+
+	case *ast.RawHTML:
+		for i := 0; i < n.Segments.Len(); i++ {
+			seg := n.Segments.At(i)
+			b.WriteString(styleWords(string(seg.Value(r.source)), style))
+		}
+
+	case *extast.TaskCheckBox:
+		// Drawn in place of the list marker
+
+	default:
+		b.WriteString(r.inlines(n, style))
+	}
+}
+
+// noteLink styles a link to another note and records it. The first word
+// carries a marker so RenderMarkdown can find the line it wraps onto.
+func (r *markdownRenderer) noteLink(link PreviewLink) string {
+	index := len(r.links)
+	r.links = append(r.links, link)
+
+	style := r.style.Link
+	switch {
+	case index == r.opts.ActiveLink:
+		style = r.style.LinkActive
+	case r.opts.IsBroken != nil && r.opts.IsBroken(link):
+		style = r.style.LinkBroken
+	}
+	return fmt.Sprintf("\x1b]notes-link;%d\x07", index) + styleWords(link.Text, style)
+}
+
+// plainText returns the unstyled text of node's inline children
+func (r *markdownRenderer) plainText(node ast.Node) string {
+	var b strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch n := child.(type) {
+		case *ast.Text:
+			b.Write(n.Segment.Value(r.source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				b.WriteString(" ")
 			}
-			
-			result = append(result, formatted)
+		case *ast.String:
+			b.Write(n.Value)
+		case *wikiLink:
+			b.WriteString(n.link.Text)
+		default:
+			b.WriteString(r.plainText(n))
 		}
 	}
-	
-	return strings.Join(result, "\n")
-}
\ No newline at end of file
+	return b.String()
+}
+
+// styleWords renders each run of non-space characters in s with style,
+// leaving the spaces between them plain
+func styleWords(s string, style lipgloss.Style) string {
+	var b strings.Builder
+	start := -1
+	for i, c := range s {
+		if c == ' ' || c == '\t' || c == '\n' {
+			if start >= 0 {
+				b.WriteString(style.Render(s[start:i]))
+				start = -1
+			}
+			b.WriteRune(c)
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		b.WriteString(style.Render(s[start:]))
+	}
+	return b.String()
+}
+
+// wikiLink is a [[Note Title]] or [[denote:ID]] link in the parsed note
+type wikiLink struct {
+	ast.BaseInline
+	link PreviewLink
+}
+
+var kindWikiLink = ast.NewNodeKind("WikiLink")
+
+// Kind implements ast.Node
+func (n *wikiLink) Kind() ast.NodeKind {
+	return kindWikiLink
+}
+
+// Dump implements ast.Node
+func (n *wikiLink) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Target": n.link.Target}, nil)
+}
+
+// wikiLinkParser parses double-bracket links before the standard link
+// parser sees their brackets
+type wikiLinkParser struct{}
+
+// Trigger implements parser.InlineParser
+func (wikiLinkParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+// Parse implements parser.InlineParser
+func (wikiLinkParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	match := previewWikiLink.FindSubmatch(line)
+	if match == nil {
+		return nil
+	}
+	block.Advance(len(match[0]))
+
+	target, label, hasLabel := strings.Cut(string(match[1]), "|")
+	target = strings.TrimSpace(target)
+	link := PreviewLink{Kind: LinkWiki, Target: target, Text: target}
+	if id, ok := strings.CutPrefix(target, "denote:"); ok {
+		link.Kind = LinkDenote
+		link.Target = strings.TrimSpace(id)
+	}
+	if hasLabel {
+		link.Text = strings.TrimSpace(label)
+	}
+	return &wikiLink{link: link}
+}
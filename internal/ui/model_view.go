@@ -40,11 +40,7 @@ type ModelAdapter struct {
 	OldDays        int
 	
 	// Filters
-	TaskFilter   bool
-	TagFilter    bool
-	TextFilter   bool
-	DailyFilter  bool
-	OldFilter    bool
+	Filters      []string // labels of the model's filter stack, in order
 	
 	// Display
 	Width  int
@@ -126,12 +122,7 @@ func (m *ModelAdapter) createViewState() ViewState {
 		PreviewScroll:  m.PreviewScroll,
 		DeleteTarget:   m.getDisplayName(m.DeleteFile),
 		
-		TaskFilter:     m.TaskFilter,
-		TagFilter:      m.TagFilter,
-		TextFilter:     m.TextFilter,
-		DailyFilter:    m.DailyFilter,
-		OldFilter:      m.OldFilter,
-		OldDays:        m.OldDays,
+		Filters:        m.Filters,
 		
 		CurrentSort:    m.CurrentSort,
 		ReversedSort:   m.ReversedSort,
@@ -183,6 +174,7 @@ func (m *ModelAdapter) getDisplayName(filepath string) string {
 //   m.uiAdapter = &ModelAdapter{
 //       Files: m.files,
 //       Filtered: m.filtered,
+//       Filters: m.filterLabels(),
 //       // ... map all fields
 //   }
 //   m.uiAdapter.InitializeUI()
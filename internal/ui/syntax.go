@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SyntaxStyle colors the tokens of highlighted code blocks
+type SyntaxStyle struct {
+	Keyword  lipgloss.Style
+	Type     lipgloss.Style // type names and builtins
+	Function lipgloss.Style
+	Variable lipgloss.Style // variables, attributes and mapping keys
+	String   lipgloss.Style
+	Number   lipgloss.Style
+	Comment  lipgloss.Style
+	Operator lipgloss.Style
+}
+
+// highlightCode splits code into lines with each token styled by its kind.
+// Code in a language without a lexer is styled with base alone, and ok is
+// false.
+func highlightCode(code, language string, syntax SyntaxStyle, base lipgloss.Style) (lines []string, ok bool) {
+	lexer := lexers.Get(language)
+	if language == "" || lexer == nil {
+		return nil, false
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		return nil, false
+	}
+
+	var line strings.Builder
+	for _, token := range iterator.Tokens() {
+		style := syntax.tokenStyle(token.Type).Inherit(base)
+		parts := strings.Split(token.Value, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, line.String())
+				line.Reset()
+			}
+			if part != "" {
+				line.WriteString(style.Render(part))
+			}
+		}
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines, true
+}
+
+// tokenStyle picks the style for a token type, falling back through its
+// category so e.g. every kind of string literal is styled as a string
+func (s SyntaxStyle) tokenStyle(t chroma.TokenType) lipgloss.Style {
+	switch {
+	case t == chroma.KeywordType, t == chroma.NameBuiltin, t == chroma.NameClass:
+		return s.Type
+	case t.InCategory(chroma.Keyword):
+		return s.Keyword
+	case t == chroma.NameFunction, t == chroma.NameFunctionMagic:
+		return s.Function
+	case t.InSubCategory(chroma.NameVariable), t == chroma.NameAttribute, t == chroma.NameTag:
+		return s.Variable
+	case t.InSubCategory(chroma.LiteralString):
+		return s.String
+	case t.InSubCategory(chroma.LiteralNumber):
+		return s.Number
+	case t.InCategory(chroma.Comment):
+		return s.Comment
+	case t.InCategory(chroma.Operator):
+		return s.Operator
+	}
+	return lipgloss.NewStyle()
+}
@@ -32,6 +32,9 @@ type Theme struct {
 	Popover     PopoverStyle
 	Dialog      DialogStyle
 	Status      StatusStyle
+	Markdown    MarkdownStyle
+	Syntax      SyntaxStyle
+	Calendar    CalendarStyle
 }
 
 // DefaultTheme returns the default color theme
@@ -67,6 +70,9 @@ func DefaultTheme() Theme {
 			Cursor:   lipgloss.NewStyle().Foreground(accent).Bold(true),
 			Item:     lipgloss.NewStyle(),
 			EmptyMsg: lipgloss.NewStyle().Foreground(muted).Italic(true),
+			Snippet:  lipgloss.NewStyle().Foreground(muted),
+			Match:    lipgloss.NewStyle().Foreground(warning).Bold(true),
+			Header:   lipgloss.NewStyle().Foreground(primary).Bold(true),
 		},
 		
 		// Modal styles
@@ -114,6 +120,54 @@ func DefaultTheme() Theme {
 			Warning: lipgloss.NewStyle().Background(warning).Foreground(lipgloss.Color("0")).Padding(0, 1),
 			Error:   lipgloss.NewStyle().Background(error).Foreground(lipgloss.Color("15")).Padding(0, 1),
 		},
+		
+		// Markdown styles
+		Markdown: MarkdownStyle{
+			Heading1:      lipgloss.NewStyle().Bold(true).Foreground(primary),
+			Heading2:      lipgloss.NewStyle().Bold(true).Foreground(secondary),
+			Heading3:      lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6")),
+			Strong:        lipgloss.NewStyle().Bold(true),
+			Emphasis:      lipgloss.NewStyle().Italic(true),
+			Strikethrough: lipgloss.NewStyle().Strikethrough(true),
+			Code:          lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+			CodeBlock:     lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+			CodeLanguage:  lipgloss.NewStyle().Foreground(muted).Italic(true),
+			CodeBorder:    lipgloss.NewStyle().Foreground(muted),
+			ListMarker:    lipgloss.NewStyle().Foreground(muted),
+			TaskOpen:      lipgloss.NewStyle().Foreground(muted),
+			TaskDone:      lipgloss.NewStyle().Foreground(success),
+			Quote:         lipgloss.NewStyle().Foreground(muted),
+			QuoteText:     lipgloss.NewStyle().Italic(true).Foreground(muted),
+			Rule:          lipgloss.NewStyle().Foreground(muted),
+			TableHeader:   lipgloss.NewStyle().Bold(true),
+			TableBorder:   lipgloss.NewStyle().Foreground(muted),
+			Link:          lipgloss.NewStyle().Foreground(primary).Underline(true),
+			LinkActive:    lipgloss.NewStyle().Foreground(accent).Reverse(true),
+			LinkBroken:    lipgloss.NewStyle().Foreground(error).Underline(true),
+			URL:           lipgloss.NewStyle().Foreground(secondary),
+			Image:         lipgloss.NewStyle().Foreground(muted).Italic(true),
+		},
+		
+		// Code highlighting styles
+		Syntax: SyntaxStyle{
+			Keyword:  lipgloss.NewStyle().Foreground(lipgloss.Color("13")),
+			Type:     lipgloss.NewStyle().Foreground(lipgloss.Color("14")),
+			Function: lipgloss.NewStyle().Foreground(primary),
+			Variable: lipgloss.NewStyle().Foreground(lipgloss.Color("11")),
+			String:   lipgloss.NewStyle().Foreground(success),
+			Number:   lipgloss.NewStyle().Foreground(accent),
+			Comment:  lipgloss.NewStyle().Italic(true).Foreground(muted),
+			Operator: lipgloss.NewStyle().Foreground(lipgloss.Color("7")),
+		},
+		
+		// Calendar styles
+		Calendar: CalendarStyle{
+			Weekday: lipgloss.NewStyle().Foreground(muted),
+			Day:     lipgloss.NewStyle(),
+			Note:    lipgloss.NewStyle().Foreground(secondary).Bold(true),
+			Today:   lipgloss.NewStyle().Foreground(accent).Underline(true),
+			Cursor:  lipgloss.NewStyle().Background(accent).Foreground(lipgloss.Color("0")).Bold(true),
+		},
 	}
 }
 
@@ -159,6 +213,9 @@ func LightTheme() Theme {
 			Cursor:   lipgloss.NewStyle().Foreground(accent).Bold(true),
 			Item:     lipgloss.NewStyle().Foreground(lipgloss.Color("235")),
 			EmptyMsg: lipgloss.NewStyle().Foreground(muted).Italic(true),
+			Snippet:  lipgloss.NewStyle().Foreground(muted),
+			Match:    lipgloss.NewStyle().Foreground(primary).Bold(true),
+			Header:   lipgloss.NewStyle().Foreground(primary).Bold(true),
 		},
 		
 		// Modal styles
@@ -206,6 +263,54 @@ func LightTheme() Theme {
 			Warning: lipgloss.NewStyle().Background(warning).Foreground(lipgloss.Color("255")).Padding(0, 1),
 			Error:   lipgloss.NewStyle().Background(error).Foreground(lipgloss.Color("255")).Padding(0, 1),
 		},
+		
+		// Markdown styles
+		Markdown: MarkdownStyle{
+			Heading1:      lipgloss.NewStyle().Bold(true).Foreground(primary),
+			Heading2:      lipgloss.NewStyle().Bold(true).Foreground(secondary),
+			Heading3:      lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("30")),
+			Strong:        lipgloss.NewStyle().Bold(true),
+			Emphasis:      lipgloss.NewStyle().Italic(true),
+			Strikethrough: lipgloss.NewStyle().Strikethrough(true),
+			Code:          lipgloss.NewStyle().Foreground(lipgloss.Color("94")),
+			CodeBlock:     lipgloss.NewStyle().Foreground(lipgloss.Color("94")),
+			CodeLanguage:  lipgloss.NewStyle().Foreground(muted).Italic(true),
+			CodeBorder:    lipgloss.NewStyle().Foreground(muted),
+			ListMarker:    lipgloss.NewStyle().Foreground(muted),
+			TaskOpen:      lipgloss.NewStyle().Foreground(muted),
+			TaskDone:      lipgloss.NewStyle().Foreground(success),
+			Quote:         lipgloss.NewStyle().Foreground(muted),
+			QuoteText:     lipgloss.NewStyle().Italic(true).Foreground(muted),
+			Rule:          lipgloss.NewStyle().Foreground(muted),
+			TableHeader:   lipgloss.NewStyle().Bold(true),
+			TableBorder:   lipgloss.NewStyle().Foreground(muted),
+			Link:          lipgloss.NewStyle().Foreground(primary).Underline(true),
+			LinkActive:    lipgloss.NewStyle().Foreground(accent).Reverse(true),
+			LinkBroken:    lipgloss.NewStyle().Foreground(error).Underline(true),
+			URL:           lipgloss.NewStyle().Foreground(secondary),
+			Image:         lipgloss.NewStyle().Foreground(muted).Italic(true),
+		},
+		
+		// Code highlighting styles
+		Syntax: SyntaxStyle{
+			Keyword:  lipgloss.NewStyle().Foreground(lipgloss.Color("90")),
+			Type:     lipgloss.NewStyle().Foreground(lipgloss.Color("30")),
+			Function: lipgloss.NewStyle().Foreground(primary),
+			Variable: lipgloss.NewStyle().Foreground(lipgloss.Color("130")),
+			String:   lipgloss.NewStyle().Foreground(success),
+			Number:   lipgloss.NewStyle().Foreground(accent),
+			Comment:  lipgloss.NewStyle().Italic(true).Foreground(muted),
+			Operator: lipgloss.NewStyle().Foreground(lipgloss.Color("235")),
+		},
+		
+		// Calendar styles
+		Calendar: CalendarStyle{
+			Weekday: lipgloss.NewStyle().Foreground(muted),
+			Day:     lipgloss.NewStyle().Foreground(lipgloss.Color("235")),
+			Note:    lipgloss.NewStyle().Foreground(primary).Bold(true),
+			Today:   lipgloss.NewStyle().Foreground(accent).Underline(true),
+			Cursor:  lipgloss.NewStyle().Background(accent).Foreground(lipgloss.Color("255")).Bold(true),
+		},
 	}
 }
 
@@ -236,6 +341,9 @@ func MinimalTheme() Theme {
 			Cursor:   lipgloss.NewStyle().Bold(true),
 			Item:     lipgloss.NewStyle(),
 			EmptyMsg: lipgloss.NewStyle().Foreground(muted).Italic(true),
+			Snippet:  lipgloss.NewStyle().Foreground(muted),
+			Match:    lipgloss.NewStyle().Bold(true).Underline(true),
+			Header:   lipgloss.NewStyle().Bold(true).Underline(true),
 		},
 		
 		// Modal styles
@@ -283,6 +391,54 @@ func MinimalTheme() Theme {
 			Warning: lipgloss.NewStyle().Reverse(true).Padding(0, 1),
 			Error:   lipgloss.NewStyle().Reverse(true).Bold(true).Padding(0, 1),
 		},
+		
+		// Markdown styles
+		Markdown: MarkdownStyle{
+			Heading1:      lipgloss.NewStyle().Bold(true).Foreground(accent),
+			Heading2:      lipgloss.NewStyle().Bold(true).Foreground(fg),
+			Heading3:      lipgloss.NewStyle().Bold(true).Foreground(fg),
+			Strong:        lipgloss.NewStyle().Bold(true),
+			Emphasis:      lipgloss.NewStyle().Italic(true),
+			Strikethrough: lipgloss.NewStyle().Strikethrough(true),
+			Code:          lipgloss.NewStyle().Foreground(accent),
+			CodeBlock:     lipgloss.NewStyle().Foreground(accent),
+			CodeLanguage:  lipgloss.NewStyle().Foreground(muted).Italic(true),
+			CodeBorder:    lipgloss.NewStyle().Foreground(muted),
+			ListMarker:    lipgloss.NewStyle().Foreground(muted),
+			TaskOpen:      lipgloss.NewStyle().Foreground(muted),
+			TaskDone:      lipgloss.NewStyle().Foreground(muted),
+			Quote:         lipgloss.NewStyle().Foreground(muted),
+			QuoteText:     lipgloss.NewStyle().Italic(true).Foreground(muted),
+			Rule:          lipgloss.NewStyle().Foreground(muted),
+			TableHeader:   lipgloss.NewStyle().Bold(true),
+			TableBorder:   lipgloss.NewStyle().Foreground(muted),
+			Link:          lipgloss.NewStyle().Foreground(fg).Underline(true),
+			LinkActive:    lipgloss.NewStyle().Foreground(accent).Reverse(true),
+			LinkBroken:    lipgloss.NewStyle().Foreground(fg).Underline(true),
+			URL:           lipgloss.NewStyle().Foreground(fg),
+			Image:         lipgloss.NewStyle().Foreground(muted).Italic(true),
+		},
+		
+		// Code highlighting styles
+		Syntax: SyntaxStyle{
+			Keyword:  lipgloss.NewStyle().Bold(true),
+			Type:     lipgloss.NewStyle(),
+			Function: lipgloss.NewStyle(),
+			Variable: lipgloss.NewStyle(),
+			String:   lipgloss.NewStyle(),
+			Number:   lipgloss.NewStyle(),
+			Comment:  lipgloss.NewStyle().Italic(true).Foreground(muted),
+			Operator: lipgloss.NewStyle(),
+		},
+		
+		// Calendar styles
+		Calendar: CalendarStyle{
+			Weekday: lipgloss.NewStyle().Foreground(muted),
+			Day:     lipgloss.NewStyle(),
+			Note:    lipgloss.NewStyle().Bold(true),
+			Today:   lipgloss.NewStyle().Underline(true),
+			Cursor:  lipgloss.NewStyle().Reverse(true),
+		},
 	}
 }
 
@@ -323,6 +479,9 @@ func HighContrastTheme() Theme {
 			Cursor:   lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Bold(true),
 			Item:     lipgloss.NewStyle().Foreground(lipgloss.Color("15")),
 			EmptyMsg: lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+			Snippet:  lipgloss.NewStyle().Foreground(lipgloss.Color("7")),
+			Match:    lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Bold(true).Underline(true),
+			Header:   lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true),
 		},
 		
 		Modal: ModalStyle{
@@ -364,5 +523,53 @@ func HighContrastTheme() Theme {
 			Warning: lipgloss.NewStyle().Background(lipgloss.Color("11")).Foreground(lipgloss.Color("0")).Padding(0, 1),
 			Error:   lipgloss.NewStyle().Background(lipgloss.Color("9")).Foreground(lipgloss.Color("15")).Padding(0, 1),
 		},
+		
+		// Markdown styles
+		Markdown: MarkdownStyle{
+			Heading1:      lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11")),
+			Heading2:      lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11")),
+			Heading3:      lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15")),
+			Strong:        lipgloss.NewStyle().Bold(true),
+			Emphasis:      lipgloss.NewStyle().Italic(true),
+			Strikethrough: lipgloss.NewStyle().Strikethrough(true),
+			Code:          lipgloss.NewStyle().Foreground(lipgloss.Color("14")),
+			CodeBlock:     lipgloss.NewStyle().Foreground(lipgloss.Color("14")),
+			CodeLanguage:  lipgloss.NewStyle().Foreground(lipgloss.Color("7")).Italic(true),
+			CodeBorder:    lipgloss.NewStyle().Foreground(lipgloss.Color("15")),
+			ListMarker:    lipgloss.NewStyle().Foreground(lipgloss.Color("11")),
+			TaskOpen:      lipgloss.NewStyle().Foreground(lipgloss.Color("11")),
+			TaskDone:      lipgloss.NewStyle().Foreground(lipgloss.Color("10")),
+			Quote:         lipgloss.NewStyle().Foreground(lipgloss.Color("15")),
+			QuoteText:     lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("7")),
+			Rule:          lipgloss.NewStyle().Foreground(lipgloss.Color("15")),
+			TableHeader:   lipgloss.NewStyle().Bold(true).Underline(true),
+			TableBorder:   lipgloss.NewStyle().Foreground(lipgloss.Color("15")),
+			Link:          lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Underline(true),
+			LinkActive:    lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Reverse(true),
+			LinkBroken:    lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Underline(true),
+			URL:           lipgloss.NewStyle().Foreground(lipgloss.Color("14")),
+			Image:         lipgloss.NewStyle().Foreground(lipgloss.Color("7")).Italic(true),
+		},
+		
+		// Code highlighting styles
+		Syntax: SyntaxStyle{
+			Keyword:  lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11")),
+			Type:     lipgloss.NewStyle().Foreground(lipgloss.Color("14")),
+			Function: lipgloss.NewStyle().Foreground(lipgloss.Color("15")),
+			Variable: lipgloss.NewStyle().Foreground(lipgloss.Color("14")),
+			String:   lipgloss.NewStyle().Foreground(lipgloss.Color("10")),
+			Number:   lipgloss.NewStyle().Foreground(lipgloss.Color("13")),
+			Comment:  lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("7")),
+			Operator: lipgloss.NewStyle().Foreground(lipgloss.Color("15")),
+		},
+		
+		// Calendar styles
+		Calendar: CalendarStyle{
+			Weekday: lipgloss.NewStyle().Foreground(lipgloss.Color("7")),
+			Day:     lipgloss.NewStyle().Foreground(lipgloss.Color("15")),
+			Note:    lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true),
+			Today:   lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Underline(true),
+			Cursor:  lipgloss.NewStyle().Background(lipgloss.Color("11")).Foreground(lipgloss.Color("0")).Bold(true),
+		},
 	}
 }
\ No newline at end of file
@@ -2,11 +2,28 @@ package ui
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// PeriodKey is a kind of periodic note and the key that opens it
+type PeriodKey struct {
+	Name string // daily, weekly, monthly or quarterly
+	Key  string
+}
+
+// markKey shows key inside word when word starts with it ("[d]aily"),
+// or in front of it otherwise ("[x] daily")
+func markKey(word, key string) string {
+	if len(key) == 1 && len(word) > 0 && strings.EqualFold(word[:1], key) {
+		return "[" + key + "]" + word[1:]
+	}
+	return "[" + key + "] " + word
+}
+
 // ViewState represents the current view configuration
 type ViewState struct {
 	Mode            ViewMode
@@ -20,19 +37,73 @@ type ViewState struct {
 	
 	// Mode-specific data
 	SearchQuery     string
+	ContentSearch   bool       // is the search matching note bodies?
+	SnippetQuery    string     // query to highlight inside snippets
+	Details         [][]string // snippet lines under each filtered file
+	Matches         [][]int    // fuzzy-matched rune positions in each filtered file
 	SelectedFile    string
 	PreviewContent  string
 	PreviewScroll   int
+	SplitPreview    string // rendered note for the split layout's preview pane
+	SplitTitle      string // name of the note in the preview pane
 	DeleteTarget    string
 	StatusMessage   StatusMessage
 	
 	// Filter states
-	TaskFilter      bool
-	TagFilter       bool
-	TextFilter      bool
-	DailyFilter     bool
-	OldFilter       bool
-	OldDays         int
+	Filters         []string // labels of the active filters, in order
+	ActiveView      string   // name of the saved view in use
+	
+	// Saved view picker
+	Views           []string
+	ViewSummaries   []string
+	ViewCursor      int
+	
+	// Backlinks panel
+	BacklinkTarget   string
+	Backlinks        []string   // display names of linking notes
+	BacklinkContexts [][]string // linking lines under each note
+	BacklinkCursor   int
+	
+	// Task view
+	Tasks           []string
+	TaskDetails     [][]string // note and line of each task
+	TaskHeaders     []string   // agenda section starting at each task
+	TaskAgenda      bool
+	TaskSort        string
+	TaskCursor      int
+	TaskTag         string
+	TaskShowDone    bool
+	TaskTagMode     bool // is the tag input open?
+	
+	// Periodic notes
+	Periods         []PeriodKey
+	
+	// Daily note calendar
+	CalendarDay     time.Time
+	CalendarNotes   map[string]bool
+	
+	// Denote sync dialog
+	SyncTarget      string
+	SyncDiff        []string
+	
+	// Trash browser
+	TrashItems      []string
+	TrashDetails    [][]string // deletion time under each note
+	TrashCursor     int
+	TrashPurge      bool
+	
+	// Frontmatter metadata editor
+	MetaTarget      string
+	MetaRows        []string
+	MetaHeaders     []string
+	MetaCursor      int
+	MetaEdit        string
+	MetaSuggestions []string
+	
+	// Template picker for a new note
+	PendingTitle    string
+	Templates       []string
+	TemplateCursor  int
 	
 	// Sort state
 	CurrentSort     string
@@ -52,6 +123,15 @@ const (
 	ModeTagCreate
 	ModeSort
 	ModeOldFilter
+	ModeViewPicker
+	ModeBacklinks
+	ModeTasks
+	ModeTemplatePicker
+	ModeCalendar
+	ModePeriodMenu
+	ModeMetadata
+	ModeSync
+	ModeTrash
 	ModeDelete
 	ModePreview
 	ModeLoading
@@ -102,6 +182,7 @@ func (v *ViewComposer) renderHeader() string {
 	header := Header{
 		Title:     "Notes",
 		FileCount: len(v.state.Filtered),
+		ViewName:  v.state.ActiveView,
 		Filters:   filters,
 		SortInfo:  sortInfo,
 		Width:     v.state.Width,
@@ -136,13 +217,49 @@ func (v *ViewComposer) renderContent() string {
 		return v.renderSortMode()
 	case ModeOldFilter:
 		return v.renderOldFilterMode()
+	case ModeViewPicker:
+		return v.renderViewPickerMode()
+	case ModeBacklinks:
+		return v.renderBacklinksMode()
+	case ModeTasks:
+		return v.renderTasksMode()
+	case ModeTemplatePicker:
+		return v.renderTemplatePickerMode()
+	case ModeCalendar:
+		return v.renderCalendarMode()
+	case ModePeriodMenu:
+		return v.renderPeriodMenuMode()
+	case ModeMetadata:
+		return v.renderMetadataMode()
+	case ModeSync:
+		return v.renderSyncMode()
+	case ModeTrash:
+		return v.renderTrashMode()
 	case ModeDelete:
 		return v.renderDeleteMode()
 	default:
+		if v.state.Layout.Split != SplitNone {
+			return v.renderSplit()
+		}
 		return v.renderFileList()
 	}
 }
 
+// renderSplit shows the file list with the note under the cursor beside it
+func (v *ViewComposer) renderSplit() string {
+	_, size := v.state.Layout.Panes()
+	
+	pane := PreviewPane{
+		Title:   v.state.SplitTitle,
+		Content: v.state.SplitPreview,
+		Width:   size.Width,
+		Height:  size.Height,
+		Style:   v.state.Theme.Popover,
+	}
+	
+	return v.state.Layout.JoinPanes(v.renderFileList(), pane.View())
+}
+
 // renderFooter creates the footer with help text
 func (v *ViewComposer) renderFooter() string {
 	if v.state.Mode == ModeNormal {
@@ -155,17 +272,20 @@ func (v *ViewComposer) renderFooter() string {
 
 // renderFileList creates the main file list view
 func (v *ViewComposer) renderFileList() string {
-	contentWidth, contentHeight := v.state.Layout.ContentArea()
+	pane, _ := v.state.Layout.Panes()
 	
 	list := ListView{
 		Items:        v.state.Filtered,
+		Details:      v.state.Details,
+		Matches:      v.state.Matches,
 		Cursor:       v.state.Cursor,
-		Width:        contentWidth,
-		Height:       contentHeight - 6, // Reserve space for header/footer
+		Width:        pane.Width,
+		Height:       pane.Height,
 		ShowCursor:   true,
 		EmptyMessage: "No files found.",
 		Style:        v.state.Theme.List,
 	}
+	list.Highlight = v.state.SnippetQuery
 	
 	if len(v.state.Files) == 0 {
 		list.EmptyMessage = "No markdown files found."
@@ -187,10 +307,14 @@ func (v *ViewComposer) renderSearchMode() string {
 		Title:    "",
 		Prompt:   "Search:",
 		Input:    input,
-		HelpText: "[Enter] apply filter [Esc] cancel",
+		HelpText: "[Enter] apply filter [Tab] search contents [Esc] cancel",
 		Width:    v.state.Width * 70 / 100,
 		Style:    v.state.Theme.Modal,
 	}
+	if v.state.ContentSearch {
+		modal.Prompt = "Content:"
+		modal.HelpText = "[Enter] apply filter [Tab] search filenames [Esc] cancel"
+	}
 	
 	// Show filtered results below
 	listView := v.renderFileList()
@@ -256,6 +380,153 @@ func (v *ViewComposer) renderTagCreateMode() string {
 }
 
 
+// renderTemplatePickerMode lists the templates a new note can start from
+func (v *ViewComposer) renderTemplatePickerMode() string {
+	contentWidth, contentHeight := v.state.Layout.ContentArea()
+	
+	list := ListView{
+		Items:        v.state.Templates,
+		Cursor:       v.state.TemplateCursor,
+		Width:        contentWidth,
+		Height:       contentHeight - 8,
+		ShowCursor:   true,
+		EmptyMessage: "No templates.",
+		Style:        v.state.Theme.List,
+	}
+	
+	var content string
+	content += v.state.Theme.Modal.Title.Render("Template for: "+v.state.PendingTitle) + "\n\n"
+	content += list.View() + "\n\n"
+	content += v.state.Theme.Modal.Help.Render("[j/k] move [Enter] create note [Esc] cancel")
+	
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
+
+// renderCalendarMode shows a month of days, marking those with a daily note
+func (v *ViewComposer) renderCalendarMode() string {
+	contentWidth, _ := v.state.Layout.ContentArea()
+	
+	calendar := CalendarView{
+		Selected: v.state.CalendarDay,
+		Today:    time.Now(),
+		Marked:   v.state.CalendarNotes,
+		Style:    v.state.Theme.Calendar,
+	}
+	
+	status := "No daily note"
+	if v.state.CalendarNotes[v.state.CalendarDay.Format("2006-01-02")] {
+		status = "Has a daily note"
+	}
+	
+	var content string
+	content += v.state.Theme.Modal.Title.Render("Daily Notes") + "\n\n"
+	content += calendar.View() + "\n\n"
+	content += v.state.CalendarDay.Format("Monday, January 2, 2006") + "  " + v.state.Theme.List.Snippet.Render(status) + "\n\n"
+	content += v.state.Theme.Modal.Help.Render("[h/l] day [j/k] week [H/L] month [t] today [Enter] open or create [Esc] close")
+	
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
+
+// renderPeriodMenuMode offers the kinds of periodic note to list
+func (v *ViewComposer) renderPeriodMenuMode() string {
+	contentWidth, _ := v.state.Layout.ContentArea()
+	
+	var content string
+	content += v.state.Theme.Modal.Title.Render("Periodic Notes") + "\n\n"
+	content += "Show notes by period:\n"
+	var choices []string
+	for _, p := range v.state.Periods {
+		choices = append(choices, fmt.Sprintf("[%s] %s", p.Key, strings.ToUpper(p.Name[:1])+p.Name[1:]))
+	}
+	content += strings.Join(choices, "  ") + "\n\n"
+	content += v.state.Theme.Modal.Help.Render("[Esc] cancel")
+	
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
+
+// renderMetadataMode shows a note's frontmatter as a form of keys and tags
+func (v *ViewComposer) renderMetadataMode() string {
+	contentWidth, contentHeight := v.state.Layout.ContentArea()
+	
+	list := ListView{
+		Items:        v.state.MetaRows,
+		Headers:      v.state.MetaHeaders,
+		Cursor:       v.state.MetaCursor,
+		Width:        contentWidth,
+		Height:       contentHeight - 10,
+		ShowCursor:   v.state.MetaEdit == "",
+		EmptyMessage: "No frontmatter.",
+		Style:        v.state.Theme.List,
+	}
+	
+	var content string
+	content += v.state.Theme.Modal.Title.Render("Metadata: "+v.state.MetaTarget) + "\n\n"
+	content += list.View() + "\n\n"
+	input, ok := v.inputs["meta"]
+	switch {
+	case !ok || v.state.MetaEdit == "":
+		content += v.state.Theme.Modal.Help.Render("[j/k] move [Enter] edit [a] add tag [n] new key [x] remove [s] save [Esc] cancel")
+	case v.state.MetaEdit == "tag":
+		content += v.state.Theme.Modal.Prompt.Render("Tag: ") + input.View() + "\n"
+		if suggestions := v.state.MetaSuggestions; len(suggestions) > 0 {
+			if len(suggestions) > 8 {
+				suggestions = append(suggestions[:8:8], "…")
+			}
+			content += v.state.Theme.List.Snippet.Render(strings.Join(suggestions, "  ")) + "\n"
+		}
+		content += v.state.Theme.Modal.Help.Render("[Tab] complete [Enter] add [Esc] cancel")
+	case v.state.MetaEdit == "key":
+		content += v.state.Theme.Modal.Prompt.Render("New key: ") + input.View() + "\n"
+		content += v.state.Theme.Modal.Help.Render("[Enter] add [Esc] cancel")
+	default:
+		content += v.state.Theme.Modal.Prompt.Render("Value: ") + input.View() + "\n"
+		content += v.state.Theme.Modal.Help.Render("[Enter] set [Esc] cancel")
+	}
+	
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
+
+// renderSyncMode shows where a Denote note's filename and frontmatter
+// disagree and what keeping either side would change
+func (v *ViewComposer) renderSyncMode() string {
+	contentWidth, _ := v.state.Layout.ContentArea()
+	
+	var content string
+	content += v.state.Theme.Modal.Title.Render("Sync: "+v.state.SyncTarget) + "\n\n"
+	content += strings.Join(v.state.SyncDiff, "\n") + "\n\n"
+	content += v.state.Theme.Modal.Help.Render("[f] frontmatter wins [n] filename wins [s] skip [F/N] same for every drifted note [Esc] stop")
+	
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
+
+// renderTrashMode lists deleted notes that can be restored or purged
+func (v *ViewComposer) renderTrashMode() string {
+	contentWidth, contentHeight := v.state.Layout.ContentArea()
+	
+	list := ListView{
+		Items:        v.state.TrashItems,
+		Details:      v.state.TrashDetails,
+		Cursor:       v.state.TrashCursor,
+		Width:        contentWidth,
+		Height:       contentHeight - 8,
+		ShowCursor:   true,
+		EmptyMessage: "The trash is empty.",
+		Style:        v.state.Theme.List,
+	}
+	
+	var content string
+	content += v.state.Theme.Modal.Title.Render("Trash") + "\n\n"
+	content += list.View() + "\n\n"
+	if v.state.TrashPurge && v.state.TrashCursor < len(v.state.TrashItems) {
+		content += v.state.Theme.Modal.Prompt.Render(fmt.Sprintf("Delete '%s' permanently?", v.state.TrashItems[v.state.TrashCursor])) + "\n"
+		content += v.state.Theme.Modal.Help.Render("[y] yes [n] no")
+	} else {
+		content += v.state.Theme.Modal.Help.Render("[j/k] move [Enter] restore [x] delete permanently [Esc] close")
+	}
+	
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
+
 // renderSortMode creates the sort selection interface
 func (v *ViewComposer) renderSortMode() string {
 	contentWidth, _ := v.state.Layout.ContentArea()
@@ -288,11 +559,108 @@ func (v *ViewComposer) renderOldFilterMode() string {
 	return modal.View()
 }
 
+// renderViewPickerMode lists the saved views to choose from
+func (v *ViewComposer) renderViewPickerMode() string {
+	contentWidth, contentHeight := v.state.Layout.ContentArea()
+	
+	details := make([][]string, len(v.state.ViewSummaries))
+	for i, summary := range v.state.ViewSummaries {
+		details[i] = []string{summary}
+	}
+	
+	list := ListView{
+		Items:        v.state.Views,
+		Details:      details,
+		Cursor:       v.state.ViewCursor,
+		Width:        contentWidth,
+		Height:       contentHeight - 8,
+		ShowCursor:   true,
+		EmptyMessage: "No saved views.",
+		Style:        v.state.Theme.List,
+	}
+	
+	var content string
+	content += v.state.Theme.Modal.Title.Render("Saved Views") + "\n\n"
+	content += list.View() + "\n\n"
+	content += v.state.Theme.Modal.Help.Render("[j/k] move [Enter] apply view [Esc] cancel")
+	
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
+
+// renderBacklinksMode lists the notes linking to the selected note
+func (v *ViewComposer) renderBacklinksMode() string {
+	contentWidth, contentHeight := v.state.Layout.ContentArea()
+	
+	list := ListView{
+		Items:        v.state.Backlinks,
+		Details:      v.state.BacklinkContexts,
+		Cursor:       v.state.BacklinkCursor,
+		Width:        contentWidth,
+		Height:       contentHeight - 8,
+		ShowCursor:   true,
+		EmptyMessage: "No notes link here.",
+		Style:        v.state.Theme.List,
+	}
+	
+	var content string
+	content += v.state.Theme.Modal.Title.Render("Backlinks: "+v.state.BacklinkTarget) + "\n\n"
+	content += list.View() + "\n\n"
+	content += v.state.Theme.Modal.Help.Render("[j/k] move [Enter] preview [e] edit [Esc] close")
+	
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
+
+// renderTasksMode lists tasks across notes, with the note and line of each
+func (v *ViewComposer) renderTasksMode() string {
+	contentWidth, contentHeight := v.state.Layout.ContentArea()
+	
+	list := ListView{
+		Items:        v.state.Tasks,
+		Details:      v.state.TaskDetails,
+		Headers:      v.state.TaskHeaders,
+		Cursor:       v.state.TaskCursor,
+		Width:        contentWidth,
+		Height:       contentHeight - 8,
+		ShowCursor:   true,
+		EmptyMessage: "No open tasks.",
+		Style:        v.state.Theme.List,
+	}
+	if v.state.TaskShowDone {
+		list.EmptyMessage = "No tasks."
+	}
+	
+	title := fmt.Sprintf("Tasks (%d)", len(v.state.Tasks))
+	if v.state.TaskAgenda {
+		title = fmt.Sprintf("Agenda (%d)", len(v.state.Tasks))
+	}
+	if v.state.TaskTag != "" {
+		title += " #" + v.state.TaskTag
+	}
+	if v.state.TaskShowDone {
+		title += " incl. done"
+	}
+	if v.state.TaskSort != "" {
+		title += " by " + v.state.TaskSort
+	}
+	
+	var content string
+	content += v.state.Theme.Modal.Title.Render(title) + "\n\n"
+	content += list.View() + "\n\n"
+	if input, ok := v.inputs["tasktag"]; ok && v.state.TaskTagMode {
+		content += v.state.Theme.Modal.Prompt.Render("Tag: ") + input.View() + "\n"
+		content += v.state.Theme.Modal.Help.Render("[Enter] apply [Esc] cancel")
+	} else {
+		content += v.state.Theme.Modal.Help.Render("[j/k] move [Space] toggle [Enter] edit at line [Tab] agenda [s] sort [#] tag [a] show done [Esc] close")
+	}
+	
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
+
 // renderDeleteMode creates the delete confirmation dialog
 func (v *ViewComposer) renderDeleteMode() string {
 	dialog := ConfirmDialog{
 		Title:   "Delete Note",
-		Message: fmt.Sprintf("Delete '%s'?", v.state.DeleteTarget),
+		Message: fmt.Sprintf("Move '%s' to the trash?", v.state.DeleteTarget),
 		Options: []DialogOption{
 			{Key: "y", Label: "yes"},
 			{Key: "n", Label: "no"},
@@ -345,23 +713,55 @@ func (v *ViewComposer) renderHelpBar() string {
 		{Key: "/", Desc: "search"},
 		{Key: "Enter", Desc: "preview"},
 		{Key: "D", Desc: "all [D]aily"},
+		{Key: "P", Desc: "[P]eriodic"},
 		{Key: "t", Desc: "open [t]asks"},
+		{Key: "T", Desc: "[T]ask list"},
 		{Key: "#", Desc: "tags"},
 		{Key: "o", Desc: "s[o]rt"},
 		{Key: "O", Desc: "days [O]ld"},
+		{Key: "v", Desc: "[v]iews"},
+		{Key: "b", Desc: "[b]acklinks"},
 	}
 	
 	// Line 2: File operations
 	line2Items := []HelpItem{
 		{Key: "e", Desc: "[e]dit"},
+		{Key: "E", Desc: "m[E]tadata"},
+		{Key: "S", Desc: "[S]ync name"},
 		{Key: "n", Desc: "[n]ew note"},
-		{Key: "d", Desc: "[d]aily note"},
+	}
+	
+	// Periodic note keys are configurable: daily on its own, the rest
+	// together as "[W]eek/[M]onth/[Q]uarter"
+	periodWords := map[string]string{"weekly": "Week", "monthly": "Month", "quarterly": "Quarter"}
+	var others []string
+	otherKey := ""
+	for _, p := range v.state.Periods {
+		if p.Name == "daily" {
+			line2Items = append(line2Items, HelpItem{Key: p.Key, Desc: markKey("daily note", p.Key)})
+			continue
+		}
+		if otherKey == "" {
+			otherKey = p.Key
+		}
+		others = append(others, markKey(periodWords[p.Name], p.Key))
+	}
+	if len(others) > 0 {
+		line2Items = append(line2Items, HelpItem{Key: otherKey, Desc: strings.Join(others, "/")})
+	}
+	line2Items = append(line2Items, HelpItem{Key: "C", Desc: "[C]alendar"})
+	
+	// Filters stack up, so offer a way to peel the last one off
+	if len(v.state.Filters) > 0 {
+		line2Items = append(line2Items, HelpItem{Key: "⌫", Desc: "pop filter"})
 	}
 	
 	// Add remaining operations
 	line2Items = append(line2Items,
 		HelpItem{Key: "R", Desc: "Denote [R]ename"},
 		HelpItem{Key: "X", Desc: "delete"},
+		HelpItem{Key: "u", Desc: "[u]ndo"},
+		HelpItem{Key: "U", Desc: "trash"},
 		HelpItem{Key: "q", Desc: "[q]uit"},
 	)
 	
@@ -385,23 +785,7 @@ func (v *ViewComposer) renderHelpBar() string {
 // Helper methods
 
 func (v *ViewComposer) getActiveFilters() []string {
-	var filters []string
-	if v.state.TaskFilter {
-		filters = append(filters, "Tasks")
-	}
-	if v.state.TagFilter {
-		filters = append(filters, "Tag")
-	}
-	if v.state.TextFilter {
-		filters = append(filters, "Search")
-	}
-	if v.state.DailyFilter {
-		filters = append(filters, "Daily")
-	}
-	if v.state.OldFilter {
-		filters = append(filters, fmt.Sprintf("Last %d days", v.state.OldDays))
-	}
-	return filters
+	return v.state.Filters
 }
 
 func (v *ViewComposer) getSortInfo() string {
@@ -0,0 +1,530 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/notes-tui/internal/ui"
+)
+
+// trashDirName is the folder inside the notes directory that deleted notes
+// are moved to. Hidden folders are never indexed, so trashed notes drop out
+// of every list.
+const trashDirName = ".trash"
+
+// journalFileName is the file in the trash that the journal is saved to,
+// so operations can be undone after notes-tui restarts and the sync and
+// migrate commands can be undone from the app
+const journalFileName = "journal.json"
+
+// snapshotDirName is the folder in the trash holding the content of
+// edited notes, one file per distinct content, named for its hash. Keeping
+// them out of the journal file means a commit only writes what is new.
+const snapshotDirName = "journal"
+
+// journalLimit caps how many operations can be undone
+const journalLimit = 100
+
+// changeKind says what an operation did to one file
+type changeKind int
+
+const (
+	changeCreate changeKind = iota // file was written where nothing was
+	changeDelete                   // file was moved to the trash
+	changeRename                   // file was moved from Path to NewPath
+	changeEdit                     // file was rewritten in place
+)
+
+// changeKindNames are how change kinds are written in the saved journal
+var changeKindNames = map[changeKind]string{
+	changeCreate: "create",
+	changeDelete: "delete",
+	changeRename: "rename",
+	changeEdit:   "edit",
+}
+
+func (k changeKind) MarshalText() ([]byte, error) {
+	return []byte(changeKindNames[k]), nil
+}
+
+func (k *changeKind) UnmarshalText(text []byte) error {
+	for kind, name := range changeKindNames {
+		if name == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown change %q", text)
+}
+
+// fileChange is one file touched by an operation, with what is needed to
+// put it back
+type fileChange struct {
+	Kind    changeKind `json:"kind"`
+	Path    string     `json:"path"`
+	NewPath string     `json:"new_path,omitempty"` // where a renamed file went
+	Trash   string     `json:"trash,omitempty"`    // where a deleted file went
+	Before  []byte     `json:"-"`                  // content before an edit
+	After   []byte     `json:"-"`                  // content after an edit, to spot later changes
+
+	// Snapshots holding Before and After once the journal is saved. They
+	// are only read back for the operation being undone.
+	BeforeSnapshot string `json:"before,omitempty"`
+	AfterSnapshot  string `json:"after,omitempty"`
+}
+
+// operation is one action the app performed, which may touch several
+// files. Changes are in the order they happened.
+type operation struct {
+	Label   string       `json:"label"`
+	Changes []fileChange `json:"changes"`
+}
+
+// journal records operations, oldest first, so they can be undone. It is
+// saved in the trash after every change and read again before the next,
+// so the app and the command line share one history.
+type journal struct {
+	path string // where the journal is saved; empty keeps it in memory
+	ops  []*operation
+}
+
+// openJournal returns the journal for a notes directory
+func openJournal(root string) journal {
+	return journal{path: filepath.Join(root, trashDirName, journalFileName)}
+}
+
+// load reads the saved journal, replacing what is in memory. A missing
+// file is an empty journal.
+func (j *journal) load() error {
+	if j.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(j.path)
+	if os.IsNotExist(err) {
+		j.ops = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var ops []*operation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return fmt.Errorf("%s: %w", j.path, err)
+	}
+	j.ops = ops
+	return nil
+}
+
+// snapshotDir is where the journal keeps the content of edited notes
+func (j *journal) snapshotDir() string {
+	return filepath.Join(filepath.Dir(j.path), snapshotDirName)
+}
+
+// save writes any new snapshots, then the journal to its file, replacing
+// it in one step. Snapshots no operation uses any more are removed.
+func (j *journal) save() error {
+	if j.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(j.snapshotDir(), 0755); err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	for _, op := range j.ops {
+		for i := range op.Changes {
+			change := &op.Changes[i]
+			if change.Kind != changeEdit {
+				continue
+			}
+			var err error
+			if change.BeforeSnapshot == "" {
+				if change.BeforeSnapshot, err = j.writeSnapshot(change.Before); err != nil {
+					return err
+				}
+			}
+			if change.AfterSnapshot == "" {
+				if change.AfterSnapshot, err = j.writeSnapshot(change.After); err != nil {
+					return err
+				}
+			}
+			used[change.BeforeSnapshot] = true
+			used[change.AfterSnapshot] = true
+		}
+	}
+
+	data, err := json.Marshal(j.ops)
+	if err != nil {
+		return err
+	}
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return err
+	}
+
+	// Only clear out old snapshots once the journal no longer names them
+	entries, _ := os.ReadDir(j.snapshotDir())
+	for _, entry := range entries {
+		if !used[entry.Name()] {
+			os.Remove(filepath.Join(j.snapshotDir(), entry.Name()))
+		}
+	}
+	return nil
+}
+
+// writeSnapshot stores content under its hash, unless it is already there,
+// and returns the snapshot's name
+func (j *journal) writeSnapshot(content []byte) (string, error) {
+	name := fmt.Sprintf("%x", sha256.Sum256(content))
+	path := filepath.Join(j.snapshotDir(), name)
+	if _, err := os.Stat(path); err == nil {
+		return name, nil
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return "", err
+	}
+	return name, os.Rename(tmp, path)
+}
+
+// readSnapshots loads the content of op's edits from their snapshots
+func (j *journal) readSnapshots(op *operation) error {
+	for i := range op.Changes {
+		change := &op.Changes[i]
+		if change.Kind != changeEdit || change.Before != nil {
+			continue
+		}
+		var err error
+		if change.Before, err = os.ReadFile(filepath.Join(j.snapshotDir(), change.BeforeSnapshot)); err != nil {
+			return err
+		}
+		if change.After, err = os.ReadFile(filepath.Join(j.snapshotDir(), change.AfterSnapshot)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newOperation starts recording an operation. Nothing is kept until it is
+// committed to the journal.
+func newOperation(label string) *operation {
+	return &operation{Label: label}
+}
+
+// edit saves a file's content before it is rewritten. Callers shouldn't
+// rewrite the file when it fails, since the change couldn't be undone.
+func (op *operation) edit(path string) error {
+	before, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	op.Changes = append(op.Changes, fileChange{Kind: changeEdit, Path: path, Before: before})
+	return nil
+}
+
+// created notes a file written where nothing was
+func (op *operation) created(path string) {
+	op.Changes = append(op.Changes, fileChange{Kind: changeCreate, Path: path})
+}
+
+// renamed notes a file moved from one path to another
+func (op *operation) renamed(from, to string) {
+	if from != to {
+		op.Changes = append(op.Changes, fileChange{Kind: changeRename, Path: from, NewPath: to})
+	}
+}
+
+// trashed notes a file moved to the trash
+func (op *operation) trashed(path, trash string) {
+	op.Changes = append(op.Changes, fileChange{Kind: changeDelete, Path: path, Trash: trash})
+}
+
+// commit adds a finished operation to the journal and saves it. Edits that
+// changed nothing are dropped, and so are operations left with no changes.
+// If the journal can't be saved the operation is still kept in memory.
+func (j *journal) commit(op *operation) error {
+	var changes []fileChange
+	for i, change := range op.Changes {
+		if change.Kind == changeEdit {
+			after, err := os.ReadFile(finalPath(op.Changes[i+1:], change.Path))
+			if err != nil || string(after) == string(change.Before) {
+				continue
+			}
+			change.After = after
+		}
+		changes = append(changes, change)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	op.Changes = changes
+
+	// Pick up anything recorded elsewhere since the journal was read
+	loadErr := j.load()
+	j.ops = append(j.ops, op)
+	if len(j.ops) > journalLimit {
+		j.ops = j.ops[len(j.ops)-journalLimit:]
+	}
+	if loadErr != nil {
+		// Don't overwrite a journal that couldn't be read
+		return loadErr
+	}
+	return j.save()
+}
+
+// finalPath follows path through any later renames
+func finalPath(later []fileChange, path string) string {
+	for _, change := range later {
+		if change.Kind == changeRename && change.Path == path {
+			path = change.NewPath
+		}
+	}
+	return path
+}
+
+// undo reverts the most recent operation in the loaded journal, returning
+// it and every path it touched. An operation that would clash with later
+// changes is left in the journal untouched; one that fails partway is
+// dropped.
+func (j *journal) undo(root string) (*operation, []string, error) {
+	if len(j.ops) == 0 {
+		return nil, nil, fmt.Errorf("nothing to undo")
+	}
+	op := j.ops[len(j.ops)-1]
+	if err := j.readSnapshots(op); err != nil {
+		return nil, nil, fmt.Errorf("content saved for undo is missing: %w", err)
+	}
+
+	// Check everything first so an undo doesn't stop halfway
+	for i := len(op.Changes) - 1; i >= 0; i-- {
+		if err := op.Changes[i].check(op.Changes[i+1:]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var touched []string
+	for i := len(op.Changes) - 1; i >= 0; i-- {
+		change := op.Changes[i]
+		paths, err := change.revert(root)
+		touched = append(touched, paths...)
+		if err != nil {
+			j.ops = j.ops[:len(j.ops)-1]
+			return op, touched, errors.Join(err, j.save())
+		}
+	}
+	j.ops = j.ops[:len(j.ops)-1]
+	return op, touched, j.save()
+}
+
+// drop removes the most recent operation without reverting it, for one
+// that can no longer be undone and would otherwise block every older one
+func (j *journal) drop() (*operation, error) {
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	if len(j.ops) == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+	op := j.ops[len(j.ops)-1]
+	j.ops = j.ops[:len(j.ops)-1]
+	return op, j.save()
+}
+
+// check reports why a change can't be reverted, given the changes after
+// it that will have been reverted first
+func (c fileChange) check(later []fileChange) error {
+	switch c.Kind {
+	case changeCreate:
+		if _, err := os.Stat(finalPath(later, c.Path)); err != nil {
+			return fmt.Errorf("%s no longer exists", filepath.Base(c.Path))
+		}
+	case changeDelete:
+		if _, err := os.Stat(c.Trash); err != nil {
+			return fmt.Errorf("%s is no longer in the trash", filepath.Base(c.Path))
+		}
+		if _, err := os.Stat(c.Path); err == nil {
+			return fmt.Errorf("%s already exists", filepath.Base(c.Path))
+		}
+	case changeRename:
+		if _, err := os.Stat(c.NewPath); err != nil {
+			return fmt.Errorf("%s no longer exists", filepath.Base(c.NewPath))
+		}
+		if _, err := os.Stat(c.Path); err == nil {
+			return fmt.Errorf("%s already exists", filepath.Base(c.Path))
+		}
+	case changeEdit:
+		current, err := os.ReadFile(finalPath(later, c.Path))
+		if err != nil {
+			return err
+		}
+		if string(current) != string(c.After) {
+			return fmt.Errorf("%s has changed since", filepath.Base(c.Path))
+		}
+	}
+	return nil
+}
+
+// revert puts one change back and returns the paths it touched. Undoing
+// a create moves the file to the trash rather than deleting it.
+func (c fileChange) revert(root string) ([]string, error) {
+	switch c.Kind {
+	case changeCreate:
+		_, err := moveToTrash(root, c.Path)
+		return []string{c.Path}, err
+	case changeDelete:
+		return []string{c.Path}, restoreFromTrash(c.Trash, c.Path)
+	case changeRename:
+		return []string{c.NewPath, c.Path}, os.Rename(c.NewPath, c.Path)
+	case changeEdit:
+		info, err := os.Stat(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		return []string{c.Path}, os.WriteFile(c.Path, c.Before, info.Mode().Perm())
+	}
+	return nil, nil
+}
+
+// record commits op to the journal and returns status, or a warning in its
+// place when the journal couldn't be saved
+func (m *model) record(op *operation, status tea.Cmd) tea.Cmd {
+	if err := m.journal.commit(op); err != nil {
+		return ui.ShowError(fmt.Sprintf("Undo history not saved, %s can only be undone until you quit: %v", op.Label, err))
+	}
+	return status
+}
+
+// loadTrash reads the trash into the browser, keeping the cursor in range
+func (m *model) loadTrash() error {
+	items, err := listTrash(m.cwd)
+	m.trashItems = items
+	if m.trashCursor >= len(items) {
+		m.trashCursor = max(len(items)-1, 0)
+	}
+	return err
+}
+
+// trashItem is a file in the trash
+type trashItem struct {
+	Path     string    // where it is in the trash
+	Original string    // where it was deleted from
+	Deleted  time.Time // when it was deleted
+}
+
+// moveToTrash moves a file into the trash under the notes directory,
+// keeping its path relative to the notes directory inside a folder named
+// for the moment it was deleted:
+//
+//	.trash/20250623T093045/projects/plan.md
+func moveToTrash(root, path string) (string, error) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(path)
+	}
+
+	stamp := time.Now().Format("20060102T150405")
+	trash := filepath.Join(root, trashDirName, stamp, rel)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(trash); os.IsNotExist(err) {
+			break
+		}
+		trash = filepath.Join(root, trashDirName, fmt.Sprintf("%s-%d", stamp, n), rel)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(trash), 0755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(path, trash); err != nil {
+		return "", err
+	}
+	return trash, nil
+}
+
+// restoreFromTrash moves a trashed file back to where it was deleted from
+// and clears away the folders it leaves empty
+func restoreFromTrash(trash, original string) error {
+	if _, err := os.Stat(original); err == nil {
+		return fmt.Errorf("%s already exists", filepath.Base(original))
+	}
+	if err := os.MkdirAll(filepath.Dir(original), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(trash, original); err != nil {
+		return err
+	}
+	removeEmptyTrashDirs(trash)
+	return nil
+}
+
+// purgeTrash deletes a trashed file for good
+func purgeTrash(item trashItem) error {
+	if err := os.Remove(item.Path); err != nil {
+		return err
+	}
+	removeEmptyTrashDirs(item.Path)
+	return nil
+}
+
+// removeEmptyTrashDirs removes the folders above a trashed file that are
+// now empty, stopping at the trash itself
+func removeEmptyTrashDirs(path string) {
+	for dir := filepath.Dir(path); filepath.Base(dir) != trashDirName; dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
+
+// listTrash returns everything in the trash, most recently deleted first
+func listTrash(root string) ([]trashItem, error) {
+	trashRoot := filepath.Join(root, trashDirName)
+	var items []trashItem
+	err := filepath.Walk(trashRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == trashRoot {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			// The journal's snapshots aren't deleted notes
+			if path == filepath.Join(trashRoot, snapshotDirName) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(trashRoot, path)
+		if err != nil {
+			return nil
+		}
+		stamp, original, ok := strings.Cut(filepath.ToSlash(rel), "/")
+		if !ok {
+			return nil
+		}
+		deleted, err := time.ParseInLocation("20060102T150405", stamp[:min(len(stamp), 15)], time.Local)
+		if err != nil {
+			deleted = info.ModTime()
+		}
+		items = append(items, trashItem{
+			Path:     path,
+			Original: filepath.Join(root, filepath.FromSlash(original)),
+			Deleted:  deleted,
+		})
+		return nil
+	})
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Deleted.After(items[j].Deleted)
+	})
+	return items, err
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// linkKind identifies how a note refers to another
+type linkKind int
+
+const (
+	linkDenote   linkKind = iota // [[denote:20250623T093045]]
+	linkWiki                     // [[Note Title]] or [[Note Title|label]]
+	linkMarkdown                 // [label](relative/path.md)
+)
+
+// noteLink is an unresolved reference found in a note's body
+type noteLink struct {
+	Kind    linkKind
+	Target  string // identifier, title or relative path as written
+	Line    int    // 1-based line number of the link
+	Context string // the line holding the link, trimmed
+}
+
+// backlink is a note that links to another, with the line it does so on
+type backlink struct {
+	Source  string // path of the linking note
+	Line    int    // 1-based line number of the link
+	Context string // the linking line, trimmed
+}
+
+var (
+	wikiLinkPattern     = regexp.MustCompile(`\[\[([^\[\]]+)\]\]`)
+	markdownLinkPattern = regexp.MustCompile(`\[[^\]]*\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+)
+
+// extractLinks finds every link to another note in content, skipping
+// frontmatter and fenced code blocks
+func extractLinks(content string) []noteLink {
+	var links []noteLink
+	eachBodyLine(content, func(num int, line string) {
+		context := strings.TrimSpace(line)
+		line = stripInlineCode(line)
+		for _, match := range wikiLinkPattern.FindAllStringSubmatch(line, -1) {
+			target, _, _ := strings.Cut(match[1], "|")
+			target = strings.TrimSpace(target)
+			if id, ok := strings.CutPrefix(target, "denote:"); ok {
+				links = append(links, noteLink{Kind: linkDenote, Target: strings.TrimSpace(id), Line: num, Context: context})
+			} else if target != "" {
+				links = append(links, noteLink{Kind: linkWiki, Target: target, Line: num, Context: context})
+			}
+		}
+		for _, match := range markdownLinkPattern.FindAllStringSubmatch(line, -1) {
+			if target, ok := relativeNotePath(match[1]); ok {
+				links = append(links, noteLink{Kind: linkMarkdown, Target: target, Line: num, Context: context})
+			}
+		}
+	})
+	return links
+}
+
+// relativeNotePath returns the file part of a markdown link destination if
+// it points at a local note file
+func relativeNotePath(dest string) (string, bool) {
+	dest = strings.Trim(dest, "<>")
+	if strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:") || strings.HasPrefix(dest, "#") {
+		return "", false
+	}
+	dest, _, _ = strings.Cut(dest, "#")
+	if unescaped, err := url.PathUnescape(dest); err == nil {
+		dest = unescaped
+	}
+	if !isNoteFile(dest) {
+		return "", false
+	}
+	return dest, true
+}
+
+// linkGraph maps every note to the notes that link to it, along with the
+// lookup tables used to resolve links
+type linkGraph struct {
+	entries      map[string]*NoteEntry
+	byIdentifier map[string]string
+	byTitle      map[string]string // lowercase title or filename stem
+	backlinks    map[string][]backlink
+	unsorted     map[string]bool // targets with backlinks added since sortBacklinks
+}
+
+// graph returns the link graph, rebuilding it if notes changed. The caller
+// must hold idx.mu for writing.
+func (idx *NoteIndex) graph() *linkGraph {
+	if idx.links == nil || idx.linksDirty {
+		idx.links = buildLinkGraph(idx.entries)
+		idx.linksDirty = false
+	}
+	return idx.links
+}
+
+// Backlinks returns the notes linking to path, grouped by source in path
+// order. The graph is rebuilt from the indexed links when notes are added,
+// removed or retitled, and patched when one is only edited.
+func (idx *NoteIndex) Backlinks(path string) []backlink {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return append([]backlink(nil), idx.graph().backlinks[path]...)
+}
+
+// ResolveLink returns the path of the note a link in source points to
+func (idx *NoteIndex) ResolveLink(source string, link noteLink) (string, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	target := idx.graph().resolve(source, link)
+	return target, target != ""
+}
+
+// resolve finds the note a link points to, or ""
+func (g *linkGraph) resolve(source string, link noteLink) string {
+	switch link.Kind {
+	case linkDenote:
+		return g.byIdentifier[link.Target]
+	case linkWiki:
+		return g.byTitle[strings.ToLower(link.Target)]
+	case linkMarkdown:
+		candidate := filepath.Clean(filepath.Join(filepath.Dir(source), filepath.FromSlash(link.Target)))
+		if _, ok := g.entries[candidate]; ok {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// buildLinkGraph resolves every indexed link to a note path
+func buildLinkGraph(entries map[string]*NoteEntry) *linkGraph {
+	graph := &linkGraph{
+		entries:      entries,
+		byIdentifier: make(map[string]string),
+		byTitle:      make(map[string]string),
+		backlinks:    make(map[string][]backlink),
+	}
+	for path, entry := range entries {
+		if entry.Identifier != "" {
+			graph.byIdentifier[entry.Identifier] = path
+		}
+		if entry.Title != "" {
+			graph.byTitle[strings.ToLower(entry.Title)] = path
+		}
+	}
+	// Wiki links may also name the file without its extension
+	for path := range entries {
+		name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+		if _, taken := graph.byTitle[name]; !taken {
+			graph.byTitle[name] = path
+		}
+	}
+
+	for source, entry := range entries {
+		graph.addLinks(source, entry)
+	}
+	graph.sortBacklinks()
+	return graph
+}
+
+// addLinks records the backlinks for each of a note's links. Call
+// sortBacklinks once the graph's links have all been added.
+func (g *linkGraph) addLinks(source string, entry *NoteEntry) {
+	for _, link := range entry.Links {
+		target := g.resolve(source, link)
+		if target == "" || target == source {
+			continue
+		}
+		g.backlinks[target] = append(g.backlinks[target], backlink{Source: source, Line: link.Line, Context: link.Context})
+		if g.unsorted == nil {
+			g.unsorted = make(map[string]bool)
+		}
+		g.unsorted[target] = true
+	}
+}
+
+// removeLinks drops the backlinks a note's earlier links added
+func (g *linkGraph) removeLinks(source string, entry *NoteEntry) {
+	for _, link := range entry.Links {
+		target := g.resolve(source, link)
+		links, ok := g.backlinks[target]
+		if !ok {
+			continue
+		}
+		links = slices.DeleteFunc(links, func(b backlink) bool { return b.Source == source })
+		if len(links) == 0 {
+			delete(g.backlinks, target)
+		} else {
+			g.backlinks[target] = links
+		}
+	}
+}
+
+// sortBacklinks puts the backlinks of every note added to since the last
+// call in source and line order
+func (g *linkGraph) sortBacklinks() {
+	for target := range g.unsorted {
+		links := g.backlinks[target]
+		sort.SliceStable(links, func(i, j int) bool {
+			if links[i].Source != links[j].Source {
+				return links[i].Source < links[j].Source
+			}
+			return links[i].Line < links[j].Line
+		})
+	}
+	g.unsorted = nil
+}
+
+// backlinkSources groups backlinks by linking note, keeping their order
+func backlinkSources(links []backlink) ([]string, map[string][]backlink) {
+	var sources []string
+	bySource := make(map[string][]backlink)
+	for _, link := range links {
+		if _, seen := bySource[link.Source]; !seen {
+			sources = append(sources, link.Source)
+		}
+		bySource[link.Source] = append(bySource[link.Source], link)
+	}
+	return sources, bySource
+}
+
+// noteLinkName returns text that links to path most likely contain, used to
+// scroll a linking note's preview to the link
+func noteLinkName(idx *NoteIndex, path string) string {
+	entry, ok := idx.Get(path)
+	if !ok {
+		return ""
+	}
+	if entry.Identifier != "" {
+		return entry.Identifier
+	}
+	return entry.Title
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -10,6 +10,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,24 +18,36 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/textinput"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/BurntSushi/toml"
+	"github.com/pdxmph/notes-tui/internal/denote"
+	"github.com/pdxmph/notes-tui/internal/frontmatter"
 	"github.com/pdxmph/notes-tui/internal/ui"
 )
 
 // Config holds application configuration
 type Config struct {
-	NotesDirectory     string   `toml:"notes_directory"`
-	Editor             string   `toml:"editor"`
-	PreviewCommand     string   `toml:"preview_command"`
-	AddFrontmatter     bool     `toml:"add_frontmatter"`
-	InitialSort        string   `toml:"initial_sort"`
-	InitialReverseSort bool     `toml:"initial_reverse_sort"`
-	DenoteFilenames    bool     `toml:"denote_filenames"`
-	ShowTitles         bool     `toml:"show_titles"`
-	PromptForTags      bool     `toml:"prompt_for_tags"`
-	Theme              string   `toml:"theme"`
-	FilteredTags       []string `toml:"filtered_tags"`
+	NotesDirectory     string                    `toml:"notes_directory"`
+	Editor             string                    `toml:"editor"`
+	EditorLineArgs     string                    `toml:"editor_line_args"` // how to open at a line, e.g. "--goto {file}:{line}"
+	PreviewCommand     string                    `toml:"preview_command"`
+	AddFrontmatter     bool                      `toml:"add_frontmatter"`
+	InitialSort        string                    `toml:"initial_sort"`
+	InitialReverseSort bool                      `toml:"initial_reverse_sort"`
+	DenoteFilenames    bool                      `toml:"denote_filenames"`
+	DenoteComponents   []string                  `toml:"denote_components"` // filename component order, e.g. ["identifier", "signature", "title", "keywords"]
+	ShowTitles         bool                      `toml:"show_titles"`
+	PromptForTags      bool                      `toml:"prompt_for_tags"`
+	Theme              string                    `toml:"theme"`
+	FilteredTags       []string                  `toml:"filtered_tags"`
+	WatchFiles         bool                      `toml:"watch_files"`
+	UseRipgrep         bool                      `toml:"use_ripgrep"`
+	SplitPane          string                    `toml:"split_pane"`
+	SplitRatio         float64                   `toml:"split_ratio"`
+	DailyCarryover     string                    `toml:"daily_carryover"` // "off", "copy" or "move"
+	TemplatesDirectory string                    `toml:"templates_directory"`
+	DailyTemplate      string                    `toml:"daily_template"` // template name for daily notes
+	Periodic           map[string]PeriodicConfig `toml:"periodic"`       // overrides keyed by daily, weekly, monthly, quarterly
+	Views              []SavedView               `toml:"views"`
 }
 
 // DefaultConfig returns a config with sensible defaults
@@ -67,6 +80,9 @@ func DefaultConfig() Config {
 		InitialReverseSort: false, // Default to normal sort order
 		Theme:              "default", // Default theme
 		FilteredTags:       []string{}, // Default to no filtering
+		WatchFiles:         true, // Pick up external edits automatically
+		SplitRatio:         0.4, // List pane share when split_pane is set
+		DailyCarryover:     carryoverOff, // Leave yesterday's tasks where they are
 	}
 }
 
@@ -161,56 +177,120 @@ func parseCommand(cmdStr string) (string, []string) {
 }
 
 type model struct {
-	files       []string        // all markdown files
-	filtered    []string        // filtered results
-	cursor      int             // which file is selected
-	selected    string          // selected file
-	searchMode  bool            // are we in search mode?
-	search      textinput.Model // search input
-	textFilter  bool            // are we showing only files matching text search?
-	createMode  bool            // are we in create mode?
-	createInput textinput.Model // create note input
-	tagMode     bool            // are we in tag search mode?
-	tagInput    textinput.Model // tag search input
-	tagFilter   bool            // are we showing only files with a specific tag?
-	taskFilter  bool            // are we showing only files with tasks?
-	dailyFilter bool            // are we showing only daily note files?
-	deleteMode  bool            // are we in delete confirmation mode?
-	deleteFile  string          // file to be deleted
+	files          []string                  // all markdown files
+	filtered       []string                  // filtered results
+	cursor         int                       // which file is selected
+	selected       string                    // selected file
+	searchMode     bool                      // are we in search mode?
+	search         textinput.Model           // search input
+	contentSearch  bool                      // does the search match note contents instead of names?
+	contentMatches map[string][]contentMatch // matching lines per file for content search
+	snippetQuery   string                    // query the content matches were found with
+	highlights     map[string][]int          // fuzzy-matched rune positions per file
+	filters        []noteFilter              // active filters, applied in order
+	activeView     string                    // name of the saved view the filters came from
+	createMode     bool                      // are we in create mode?
+	createInput    textinput.Model           // create note input
+	tagMode        bool                      // are we in tag search mode?
+	tagInput       textinput.Model           // tag search input
+	deleteMode     bool                      // are we in delete confirmation mode?
+	deleteFile     string                    // file to be deleted
 	// Tag creation state
 	tagCreateMode  bool            // are we prompting for tags during note creation?
 	tagCreateInput textinput.Model // tag input during note creation
 	pendingTitle   string          // title waiting for tags before creating note
+	pendingTags    []string        // tags waiting for a template before creating note
+	// Template picker
+	templateMode   bool           // are we choosing a template for a new note?
+	templates      []noteTemplate // templates to choose from
+	templateCursor int            // highlighted choice; 0 is a blank note
 	// Sorting state
-	sortMode     bool            // are we in sort selection mode?
-	currentSort  string          // current sort method: "date", "modified", "title", "denote", or ""
-	reversedSort bool            // is the current sort reversed?
+	sortMode     bool   // are we in sort selection mode?
+	currentSort  string // current sort method: "date", "modified", "title", "denote", or ""
+	reversedSort bool   // is the current sort reversed?
 	// Days old filter
-	oldMode      bool            // are we in days old mode?
-	oldInput     textinput.Model // days old input
-	oldFilter    bool            // are we showing only files from last N days?
-	oldDays      int             // number of days for old filter
-	cwd         string          // current working directory
-	width       int             // terminal width
-	height      int             // terminal height
-	config      Config          // application configuration
+	oldMode  bool            // are we in days old mode?
+	oldInput textinput.Model // days old input
+	// Saved view picker
+	viewMode   bool // are we picking a saved view?
+	viewCursor int  // highlighted view in the picker
+	// Backlinks panel
+	backlinksMode  bool   // are we showing notes that link to a note?
+	backlinkTarget string // note whose backlinks are shown
+	backlinkCursor int    // highlighted linking note
+	// Task view
+	taskMode     bool            // are we listing tasks across notes?
+	tasks        []taskItem      // tasks shown, in file list order
+	taskCursor   int             // highlighted task
+	taskShowDone bool            // include completed tasks?
+	taskAgenda   bool            // group tasks into Overdue / Today / This week / Someday?
+	taskSort     string          // "due", "priority", or "" for file list order
+	taskTag      string          // only show tasks from notes with this tag
+	taskTagMode  bool            // are we typing the task view's tag?
+	taskTagInput textinput.Model // tag input for the task view
+	// Daily note calendar
+	calendarMode bool      // are we picking a day from the calendar?
+	calendarDay  time.Time // day under the calendar cursor
+	// Periodic note filter menu
+	periodMode bool // are we choosing which periodic notes to list?
+	// Frontmatter metadata editor
+	metaMode        bool            // are we editing a note's frontmatter?
+	metaFile        string          // note being edited
+	metaFields      []metaField     // rows of the form
+	metaCursor      int             // highlighted row
+	metaEdit        string          // what the input is for: "value", "tag", "key", or "" when closed
+	metaInput       textinput.Model // value, tag or key being typed
+	metaCompletions []string        // known tags Tab cycles through
+	metaCompletion  int             // next completion to offer
+	// Denote filename/frontmatter sync
+	syncMode  bool         // are we choosing which side of a drifted note wins?
+	syncDrift *denoteDrift // what disagrees in the note being synced
+	syncQueue []string     // notes still to check once this one is done
+	// Undo and trash
+	journal      journal      // operations that can be undone, saved in the trash
+	trashMode    bool         // are we browsing deleted notes?
+	trashItems   []trashItem  // notes in the trash, newest first
+	trashCursor  int          // highlighted trashed note
+	trashPurge   bool         // are we confirming a permanent delete?
+	undoBlocked  bool         // can y drop an operation that couldn't be undone?
+	cwd          string       // current working directory
+	width        int          // terminal width
+	height       int          // terminal height
+	config       Config       // application configuration
+	index        *NoteIndex   // parsed metadata for every note
+	watcher      *noteWatcher // reports external changes to notes
+	startupError string       // problem with the command line, shown once the UI is up
+	startupCmd   tea.Cmd      // command line action to run once the UI is up
 	// Preview popover state
-	previewMode    bool            // are we showing preview popover?
-	previewContent string          // content for preview popover
-	previewFile    string          // file being previewed
-	previewScroll  int             // scroll position in preview
+	previewMode    bool              // are we showing preview popover?
+	previewContent string            // content for preview popover
+	previewFile    string            // file being previewed
+	previewScroll  int               // scroll position in preview
+	previewQuery   string            // content search hit to scroll to once loaded
+	previewRaw     string            // unrendered content of the previewed note
+	previewLinks   []ui.PreviewLink  // note links in the preview, in order
+	previewLink    int               // index of the active link, or -1
+	previewHistory []previewPosition // notes to return to with Backspace
+	// Split layout preview pane
+	splitFile    string             // note requested for the pane
+	splitShown   string             // note whose content is in the pane
+	splitContent string             // rendered content for the pane
+	splitGen     int                // increments with every load, to drop stale results
+	splitCancel  context.CancelFunc // cancels the load in flight
 	// Rename state
-	renameMode     bool            // are we renaming a file to Denote format?
-	renameFile     string          // file being renamed
+	renameMode bool   // are we renaming a file to Denote format?
+	renameFile string // file being renamed
 	// Navigation state
-	waitingForSecondG bool          // waiting for second 'g' in 'gg' sequence
+	waitingForSecondG bool // waiting for second 'g' in 'gg' sequence
 	// UI integration
-	ui              *ui.ModelIntegration
+	ui *ui.ModelIntegration
 }
 
 // Message for preview content
 type previewLoadedMsg struct {
 	content  string
+	raw      string
+	links    []ui.PreviewLink
 	filepath string
 }
 
@@ -218,7 +298,7 @@ type previewLoadedMsg struct {
 type clearSelectedMsg struct{}
 
 
-func initialModel(startupTag string) model {
+func initialModel(startupTag, startupQuery, startupView, startupDaily string) model {
 	// Load configuration
 	config := LoadConfig()
 	
@@ -233,10 +313,12 @@ func initialModel(startupTag string) model {
 		}
 	}
 	
-	files, err := findMarkdownFiles(cwd, config)
-	if err != nil {
+	// Parse every note once; later changes update the index incrementally
+	index := NewNoteIndex(cwd, config)
+	if err := index.Build(); err != nil {
 		log.Fatal(err)
 	}
+	files := index.Paths()
 
 	// Create search input
 	ti := textinput.New()
@@ -267,6 +349,17 @@ func initialModel(startupTag string) model {
 	oldi.Placeholder = "Days back..."
 	oldi.CharLimit = 3
 	oldi.Width = 15
+
+	// Create task view tag input
+	taski := textinput.New()
+	taski.Placeholder = "Tag (empty for all)..."
+	taski.CharLimit = 50
+	taski.Width = 30
+	
+	// Create metadata editor input
+	metai := textinput.New()
+	metai.CharLimit = 200
+	metai.Width = 50
 	
 
 	m := model{
@@ -277,8 +370,12 @@ func initialModel(startupTag string) model {
 		tagInput:       tagi,
 		tagCreateInput: tagci,
 		oldInput:       oldi,
+		taskTagInput:   taski,
+		metaInput:      metai,
 		cwd:            cwd,
 		config:         config,
+		index:          index,
+		journal:        openJournal(cwd),
 		reversedSort:   config.InitialReverseSort,
 	}
 
@@ -292,15 +389,57 @@ func initialModel(startupTag string) model {
 		}
 	}
 
+	// A bad component order falls back to Denote's, but say so
+	if _, err := denote.ParseOrder(config.DenoteComponents); err != nil {
+		m.startupError = fmt.Sprintf("denote_components: %v", err)
+	}
+
+	// Periodic note keys that clash are ignored, but say so
+	for _, p := range defaultPeriods {
+		if key := config.Periodic[p.Name].Key; key != "" {
+			if err := periodicKeyError(config, p.Name, key); err != nil {
+				m.startupError = err.Error()
+			}
+		}
+	}
+
+	// If a startup view was provided, start from its filters
+	if startupView != "" {
+		if view, ok := findView(config, startupView); !ok {
+			m.startupError = fmt.Sprintf("No saved view named %q", startupView)
+		} else if err := m.applyView(view); err != nil {
+			m.startupError = err.Error()
+		}
+	}
+
 	// If a startup tag was provided, apply tag filter
 	if startupTag != "" {
-		if tagFiles, err := searchTag(cwd, startupTag); err == nil {
-			m.filtered = tagFiles
-			m.tagFilter = true
-			m.cursor = 0
+		m.pushFilter(noteFilter{Kind: filterTag, Value: strings.TrimPrefix(startupTag, "#")})
+	}
+
+	// If a startup query was provided, apply its filters
+	if startupQuery != "" {
+		if query, err := parseQuery(startupQuery, false); err != nil {
+			m.startupError = fmt.Sprintf("Query error: %v", err)
+		} else {
+			m.pushFilter(query...)
 		}
 	}
 
+	// If a startup day was provided, open its daily note once the UI is up
+	if startupDaily != "" {
+		if day, err := parseDailyDate(startupDaily, time.Now()); err != nil {
+			m.startupError = err.Error()
+		} else {
+			m.startupCmd = m.openDailyNote(day)
+		}
+	}
+
+	// Watch for notes changed outside the app
+	if config.WatchFiles {
+		m.watcher = newNoteWatcher(cwd)
+	}
+
 	// Initialize UI integration
 	m.ui = &ui.ModelIntegration{
 		Files:              m.files,
@@ -310,36 +449,49 @@ func initialModel(startupTag string) model {
 		ShowTitles:         config.ShowTitles,
 		DenoteFilenames:    config.DenoteFilenames,
 		ThemeName:          config.Theme,
+		SplitPane:          config.SplitPane,
+		SplitRatio:         config.SplitRatio,
 		Search:             m.search,
 		CreateInput:        m.createInput,
 		TagInput:           m.tagInput,
 		TagCreateInput:     m.tagCreateInput,
 		OldInput:           m.oldInput,
+		TaskTagInput:       m.taskTagInput,
+		MetaInput:          m.metaInput,
+		DisplayName: func(path string) string {
+			return getEnhancedDisplayName(index, path, cwd, config.ShowTitles)
+		},
 	}
 
 	return m
 }
 
-// extractDenoteTags extracts tags from a Denote-style filename
-// Format: YYYYMMDDTHHMMSS--title__tag1_tag2.md
+// extractDenoteTags returns the keywords of a Denote-style filename
+// Format: YYYYMMDDTHHMMSS==signature--title__tag1_tag2.md
 func extractDenoteTags(filename string) []string {
-	// Remove path and extension
+	if name, ok := denote.Parse(filepath.Base(filename)); ok {
+		return name.Keywords
+	}
+	
+	// Other names may still carry keywords after a double underscore,
+	// e.g. project__work.md
 	base := filepath.Base(filename)
 	base = strings.TrimSuffix(base, filepath.Ext(base))
-	
-	// Look for double underscore which indicates tags
 	parts := strings.Split(base, "__")
-	if len(parts) < 2 {
+	if len(parts) < 2 || parts[1] == "" {
 		return nil
 	}
-	
-	// Tags are in the second part, separated by single underscores
-	tagPart := parts[1]
-	if tagPart == "" {
-		return nil
+	return strings.Split(parts[1], "_")
+}
+
+// denoteOrder returns the configured filename component order, or Denote's
+// standard order when none (or an invalid one) is set
+func (c Config) denoteOrder() []denote.Component {
+	order, err := denote.ParseOrder(c.DenoteComponents)
+	if err != nil {
+		return denote.DefaultOrder
 	}
-	
-	return strings.Split(tagPart, "_")
+	return order
 }
 
 func findMarkdownFiles(dir string, config Config) ([]string, error) {
@@ -358,8 +510,8 @@ func findMarkdownFiles(dir string, config Config) ([]string, error) {
 			return filepath.SkipDir
 		}
 
-		// Check if it's a markdown file
-		if !info.IsDir() && (strings.HasSuffix(strings.ToLower(info.Name()), ".md") || strings.HasSuffix(strings.ToLower(info.Name()), ".markdown")) {
+		// Check if it's a note file
+		if !info.IsDir() && isNoteFile(path) {
 			// If filtered tags are configured, check if this file should be excluded
 			if len(config.FilteredTags) > 0 {
 				// Extract tags from filename
@@ -395,45 +547,29 @@ func getDisplayName(fullPath, cwd string) string {
 
 // Extract title from a note file
 func extractNoteTitle(filepath string) string {
-	// Read the first 20 lines of the file to find title
-	file, err := os.Open(filepath)
+	content, err := os.ReadFile(filepath)
 	if err != nil {
 		return ""
 	}
-	defer file.Close()
+	return extractTitleFromContent(string(content))
+}
 
-	scanner := bufio.NewScanner(file)
-	lineCount := 0
-	inFrontmatter := false
-	
-	for scanner.Scan() && lineCount < 20 {
-		line := scanner.Text()
-		lineCount++
-		
-		// Check for YAML frontmatter
-		if lineCount == 1 && line == "---" {
-			inFrontmatter = true
-			continue
+// Extract title from note content (frontmatter title or first heading)
+func extractTitleFromContent(content string) string {
+	doc, _ := frontmatter.Parse(content)
+	if doc.Meta != nil {
+		if title := strings.TrimSpace(doc.Meta.Get("title")); title != "" {
+			return title
 		}
-		
-		// Look for title in frontmatter
-		if inFrontmatter {
-			if line == "---" {
-				inFrontmatter = false
-				continue
-			}
-			if strings.HasPrefix(line, "title:") {
-				title := strings.TrimSpace(strings.TrimPrefix(line, "title:"))
-				// Remove quotes if present
-				title = strings.Trim(title, "\"'")
-				if title != "" {
-					return title
-				}
-			}
+	}
+	
+	// Look for the first level 1 heading near the top of the body
+	lines := strings.SplitN(doc.Body, "\n", 21)
+	for i, line := range lines {
+		if i == 20 {
+			break
 		}
-		
-		// Look for first level 1 heading
-		if !inFrontmatter && strings.HasPrefix(line, "# ") {
+		if strings.HasPrefix(line, "# ") {
 			title := strings.TrimSpace(strings.TrimPrefix(line, "# "))
 			if title != "" {
 				return title
@@ -445,247 +581,112 @@ func extractNoteTitle(filepath string) string {
 }
 
 // Generate Denote-style filename from title
-func generateDenoteName(title string, tags []string, timestamp time.Time) (filename string, identifier string) {
+func generateDenoteName(title string, tags []string, timestamp time.Time, config Config) (filename string, identifier string) {
 	// Use provided timestamp
-	identifier = timestamp.Format("20060102T150405")
-	
-	// Sanitize title for filename
-	// Convert to lowercase
-	sanitized := strings.ToLower(title)
-	
-	// Replace spaces with hyphens
-	sanitized = strings.ReplaceAll(sanitized, " ", "-")
+	identifier = denote.IdentifierFor(timestamp)
 	
-	// Remove special characters, keep only alphanumeric and hyphens
-	var result strings.Builder
-	for _, ch := range sanitized {
-		if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '-' {
-			result.WriteRune(ch)
-		}
+	// Sanitize title for filename, falling back to "untitled"
+	slug := denote.SlugTitle(title)
+	if slug == "" {
+		slug = "untitled"
 	}
 	
-	// Clean up multiple hyphens
-	cleaned := regexp.MustCompile(`-+`).ReplaceAllString(result.String(), "-")
-	cleaned = strings.Trim(cleaned, "-")
-	
-	// If empty after sanitization, use "untitled"
-	if cleaned == "" {
-		cleaned = "untitled"
+	name := denote.Name{
+		Identifier: identifier,
+		Title:      slug,
+		Keywords:   denote.KeywordsFor(tags),
+		Extension:  ".md",
 	}
-	
-	// Process tags if provided
-	if len(tags) > 0 {
-		var sanitizedTags []string
-		for _, tag := range tags {
-			// Sanitize each tag similar to title
-			tagLower := strings.ToLower(tag)
-			tagLower = strings.ReplaceAll(tagLower, " ", "-")
-			
-			var tagResult strings.Builder
-			for _, ch := range tagLower {
-				if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '-' {
-					tagResult.WriteRune(ch)
-				}
-			}
-			
-			tagCleaned := regexp.MustCompile(`-+`).ReplaceAllString(tagResult.String(), "-")
-			tagCleaned = strings.Trim(tagCleaned, "-")
-			
-			if tagCleaned != "" {
-				sanitizedTags = append(sanitizedTags, tagCleaned)
-			}
-		}
-		
-		// Join tags with single underscore and append with double underscore
-		if len(sanitizedTags) > 0 {
-			tagString := strings.Join(sanitizedTags, "_")
-			filename = fmt.Sprintf("%s--%s__%s.md", identifier, cleaned, tagString)
-			return filename, identifier
-		}
-	}
-	
-	filename = fmt.Sprintf("%s--%s.md", identifier, cleaned)
-	return filename, identifier
+	return name.Format(config.denoteOrder()), identifier
 }
 
 // Parse Denote filename to extract title
 func parseDenoteFilename(filename string) (title string, timestamp time.Time) {
-	base := strings.TrimSuffix(filename, ".md")
-	
-	// Expected format: YYYYMMDDTHHMMSS--title
-	if len(base) < 17 { // Minimum length for timestamp + double hyphen
+	name, ok := denote.Parse(filename)
+	if !ok || name.Title == "" {
+		return filename, time.Time{}
+	}
+	t, ok := name.Time()
+	if !ok {
 		return filename, time.Time{}
 	}
+	return name.ReadableTitle(), t
+}
+
+// Rename a file to Denote format
+func renameToDenoteName(filepath string, config Config) (string, error) {
+	name, err := denoteNameFor(filepath)
+	if err != nil {
+		return "", err
+	}
+	newFilename := name.Format(config.denoteOrder())
 	
-	// Check if it matches Denote pattern
-	if base[8] == 'T' && len(base) > 16 && base[15:17] == "--" {
-		timestampStr := base[:15]
-		titlePart := base[17:]
+	// Create new path
+	dir := path.Dir(filepath)
+	newPath := path.Join(dir, newFilename)
+	
+	// Check if target already exists
+	if filepath != newPath {
+		if _, err := os.Stat(newPath); err == nil {
+			return "", fmt.Errorf("file already exists: %s", newFilename)
+		}
 		
-		// Try to parse timestamp
-		t, err := time.Parse("20060102T150405", timestampStr)
-		if err == nil {
-			// Convert hyphens back to spaces and capitalize
-			words := strings.Split(titlePart, "-")
-			for i, word := range words {
-				if len(word) > 0 {
-					words[i] = strings.ToUpper(string(word[0])) + word[1:]
-				}
-			}
-			return strings.Join(words, " "), t
+		// Rename the file
+		if err := os.Rename(filepath, newPath); err != nil {
+			return "", fmt.Errorf("failed to rename file: %w", err)
 		}
 	}
 	
-	return filename, time.Time{}
+	return newPath, nil
 }
 
-// Rename a file to Denote format
-func renameToDenoteName(filepath string, config Config) (string, error) {
-	// Check if file already has Denote format
+// denoteNameFor works out the Denote name a note should have
+func denoteNameFor(filepath string) (denote.Name, error) {
+	// Keep the identifier, signature and extension of a Denote name. Names
+	// from older versions that used a single - after the identifier keep
+	// their identifier too, and any other name stands in as the title.
 	filename := path.Base(filepath)
-	denotePattern := regexp.MustCompile(`^\d{8}T\d{6}--`)
-	var existingTimestamp string
-	
-	if denotePattern.MatchString(filename) {
-		// Extract existing timestamp
-		existingTimestamp = filename[:15]
+	existing, isDenote := denote.Parse(filename)
+	if !isDenote {
+		stem, ext := denote.SplitExtension(filename)
+		existing = denote.Name{Extension: ext}
+		if len(stem) > 15 && denote.IsIdentifier(stem[:15]) {
+			existing.Identifier = stem[:15]
+			stem = stem[15:]
+		}
+		existing.Title = denote.SlugTitle(stem)
 	}
 	
 	// Read file to extract title and tags
 	content, err := os.ReadFile(filepath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read file: %w", err)
+		return denote.Name{}, fmt.Errorf("failed to read file: %w", err)
 	}
 	
 	// Extract title - use the extractNoteTitle function
 	title := extractNoteTitle(filepath)
 	
-	// Extract tags from frontmatter
+	// Take tags and the original date from frontmatter
 	var tags []string
 	var yamlDate *time.Time
-	lines := strings.Split(string(content), "\n")
-	inFrontmatter := false
-	
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		
-		// Check for frontmatter boundaries
-		if i == 0 && trimmed == "---" {
-			inFrontmatter = true
-			continue
-		}
-		if inFrontmatter && trimmed == "---" {
-			break
-		}
-		
-		// Look for date in frontmatter
-		if inFrontmatter && strings.HasPrefix(trimmed, "date:") {
-			datePart := strings.TrimPrefix(trimmed, "date:")
-			datePart = strings.TrimSpace(datePart)
-			
-			// Try to parse the date - support multiple formats
-			dateFormats := []string{
-				"2006-01-02T15:04:05Z07:00", // ISO 8601 with timezone
-				"2006-01-02T15:04:05",       // ISO 8601 without timezone
-				"2006-01-02 15:04:05",       // Space separated datetime
-				"2006-01-02",                // Date only
-				"01/02/2006",                // US format
-				"02/01/2006",                // European format
-			}
-			
-			for _, format := range dateFormats {
-				if parsedDate, err := time.Parse(format, datePart); err == nil {
-					yamlDate = &parsedDate
-					break
-				}
-			}
-		}
-		
-		// Look for tags in frontmatter
-		if inFrontmatter && strings.HasPrefix(trimmed, "tags:") {
-			// Handle array format: tags: [tag1, tag2, tag3]
-			if strings.Contains(trimmed, "[") {
-				// Extract tags from array format
-				tagsPart := strings.TrimPrefix(trimmed, "tags:")
-				tagsPart = strings.TrimSpace(tagsPart)
-				tagsPart = strings.Trim(tagsPart, "[]")
-				
-				if tagsPart != "" {
-					// Split by comma and clean each tag
-					tagList := strings.Split(tagsPart, ",")
-					for _, tag := range tagList {
-						cleaned := strings.TrimSpace(tag)
-						if cleaned != "" {
-							tags = append(tags, cleaned)
-						}
-					}
-				}
-			}
+	if doc, err := frontmatter.Parse(string(content)); err == nil && doc.Meta != nil {
+		tags = doc.Meta.Strings("tags")
+		if parsedDate, ok := doc.Meta.Time("date"); ok {
+			yamlDate = &parsedDate
 		}
 	}
 	
-	// Generate new filename
-	var newFilename string
-	var identifier string
+	// Filename keywords stay when the frontmatter lists no tags
+	keywords := denote.KeywordsFor(tags)
+	if len(keywords) == 0 {
+		keywords = existing.Keywords
+	}
 	
-	if existingTimestamp != "" {
-		// Preserve existing timestamp
-		identifier = existingTimestamp
-		
-		// Sanitize title for filename
-		titleLower := strings.ToLower(title)
-		titleLower = strings.ReplaceAll(titleLower, " ", "-")
-		
-		var result strings.Builder
-		for _, ch := range titleLower {
-			if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '-' {
-				result.WriteRune(ch)
-			}
-		}
-		
-		cleaned := regexp.MustCompile(`-+`).ReplaceAllString(result.String(), "-")
-		cleaned = strings.Trim(cleaned, "-")
-		
-		if cleaned == "" {
-			cleaned = "untitled"
-		}
-		
-		// Build filename with tags if present
-		if len(tags) > 0 {
-			var sanitizedTags []string
-			for _, tag := range tags {
-				tagLower := strings.ToLower(tag)
-				tagLower = strings.ReplaceAll(tagLower, " ", "-")
-				
-				var tagResult strings.Builder
-				for _, ch := range tagLower {
-					if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '-' {
-						tagResult.WriteRune(ch)
-					}
-				}
-				
-				tagCleaned := regexp.MustCompile(`-+`).ReplaceAllString(tagResult.String(), "-")
-				tagCleaned = strings.Trim(tagCleaned, "-")
-				
-				if tagCleaned != "" {
-					sanitizedTags = append(sanitizedTags, tagCleaned)
-				}
-			}
-			
-			if len(sanitizedTags) > 0 {
-				tagString := strings.Join(sanitizedTags, "_")
-				newFilename = fmt.Sprintf("%s-%s__%s.md", identifier, cleaned, tagString)
-			} else {
-				newFilename = fmt.Sprintf("%s-%s.md", identifier, cleaned)
-			}
-		} else {
-			newFilename = fmt.Sprintf("%s-%s.md", identifier, cleaned)
-		}
-	} else {
+	if existing.Identifier == "" {
 		// Get file modification time to preserve original timestamp
 		fileInfo, err := os.Stat(filepath)
 		if err != nil {
-			return "", fmt.Errorf("failed to get file info: %w", err)
+			return denote.Name{}, fmt.Errorf("failed to get file info: %w", err)
 		}
 		
 		// Use modification time as the default timestamp
@@ -695,32 +696,21 @@ func renameToDenoteName(filepath string, config Config) (string, error) {
 		if yamlDate != nil && yamlDate.Before(timestamp) {
 			timestamp = *yamlDate
 		}
-		
-		// Use the earlier timestamp for the identifier
-		newFilename, identifier = generateDenoteName(title, tags, timestamp)
+		existing.Identifier = denote.IdentifierFor(timestamp)
 	}
 	
-	// Create new path
-	dir := path.Dir(filepath)
-	newPath := path.Join(dir, newFilename)
-	
-	// Check if target already exists
-	if filepath != newPath {
-		if _, err := os.Stat(newPath); err == nil {
-			return "", fmt.Errorf("file already exists: %s", newFilename)
-		}
-		
-		// Rename the file
-		if err := os.Rename(filepath, newPath); err != nil {
-			return "", fmt.Errorf("failed to rename file: %w", err)
-		}
+	// Sanitize title for filename, keeping the old one if the note has none
+	if slug := denote.SlugTitle(title); slug != "" {
+		existing.Title = slug
+	} else if existing.Title == "" {
+		existing.Title = "untitled"
 	}
-	
-	return newPath, nil
+	existing.Keywords = keywords
+	return existing, nil
 }
 
 // Get enhanced display name for a file (with title extraction if enabled)
-func getEnhancedDisplayName(fullPath, cwd string, showTitles bool) string {
+func getEnhancedDisplayName(idx *NoteIndex, fullPath, cwd string, showTitles bool) string {
 	// Get the basic display name first
 	basicName := getDisplayName(fullPath, cwd)
 	
@@ -728,8 +718,13 @@ func getEnhancedDisplayName(fullPath, cwd string, showTitles bool) string {
 		return basicName
 	}
 	
-	// Try to extract title
-	title := extractNoteTitle(fullPath)
+	// Use the indexed title, reading the file only if it isn't indexed
+	var title string
+	if entry, ok := idx.Get(fullPath); ok {
+		title = entry.Title
+	} else {
+		title = extractNoteTitle(fullPath)
+	}
 	
 	// If we got a title from the file content, use it
 	if title != "" {
@@ -751,24 +746,6 @@ func getEnhancedDisplayName(fullPath, cwd string, showTitles bool) string {
 	return basicName
 }
 
-// Filter files based on search query
-func filterFiles(files []string, query string) []string {
-	if query == "" {
-		return files
-	}
-
-	query = strings.ToLower(query)
-	var filtered []string
-	
-	for _, file := range files {
-		if strings.Contains(strings.ToLower(file), query) {
-			filtered = append(filtered, file)
-		}
-	}
-	
-	return filtered
-}
-
 // Convert title to filename
 func titleToFilename(title string) string {
 	// Convert to lowercase
@@ -802,180 +779,44 @@ func titleToFilename(title string) string {
 	return cleaned + ".md"
 }
 
-// Generate note content based on configuration
-func generateNoteContent(title string, config Config, identifier string, tags []string) string {
+// Generate note content based on configuration, dated the given day
+func generateNoteContent(title string, config Config, identifier string, tags []string, date time.Time) string {
 	if config.AddFrontmatter {
-		// YAML frontmatter format
-		today := time.Now().Format("2006-01-02")
-		
-		// Start building frontmatter
-		frontmatter := "---\n"
-		frontmatter += fmt.Sprintf("title: %s\n", title)
-		frontmatter += fmt.Sprintf("date: %s\n", today)
+		// YAML frontmatter; values are quoted where YAML needs it
+		meta := frontmatter.New(frontmatter.YAML)
+		meta.Set("title", title)
+		meta.SetDate("date", date)
 		
 		// Add identifier if using Denote style
 		if config.DenoteFilenames && identifier != "" {
-			frontmatter += fmt.Sprintf("identifier: %s\n", identifier)
+			meta.Set("identifier", identifier)
 		}
 		
 		// Add tags if provided
 		if len(tags) > 0 {
-			// Format as YAML array
-			tagList := strings.Join(tags, ", ")
-			frontmatter += fmt.Sprintf("tags: [%s]\n", tagList)
+			meta.Set("tags", tags)
 		}
 		
-		frontmatter += "---\n\n"
-		return frontmatter
+		block, err := meta.Encode()
+		if err != nil {
+			return fmt.Sprintf("# %s\n\n", title)
+		}
+		return block + "\n"
 	} else {
 		// Simple markdown header format
 		return fmt.Sprintf("# %s\n\n", title)
 	}
 }
 
-// Get today's daily note filename
-// Generate daily note filename based on config
-func getDailyNoteFilename(config Config) (string, string) {
-	if config.DenoteFilenames {
-		// Use Denote format for daily notes
-		// Tags could be "daily" by default
-		filename, identifier := generateDenoteName("daily", []string{"daily"}, time.Now())
-		return filename, identifier
-	}
-	// Traditional format
-	today := time.Now().Format("2006-01-02")
-	return today + "-daily.md", ""
-}
-
-// Find existing daily note for today regardless of format
-func findTodaysDailyNote(dir string) (string, error) {
-	today := time.Now()
-	
-	// Pattern 1: Traditional format (YYYY-MM-DD-daily.md)
-	traditionalName := today.Format("2006-01-02") + "-daily.md"
-	traditionalPath := filepath.Join(dir, traditionalName)
-	if _, err := os.Stat(traditionalPath); err == nil {
-		return traditionalPath, nil
-	}
-	
-	// Pattern 2: Denote format (YYYYMMDDTHHMMSS-daily*.md)
-	// Need to search for files starting with today's date in Denote format
-	todayDenote := today.Format("20060102")
-	
-	// Get all markdown files
-	files, err := findMarkdownFiles(dir, Config{})
-	if err != nil {
-		return "", err
-	}
-	
-	// Check each file for Denote daily note pattern
-	denotePattern := regexp.MustCompile(fmt.Sprintf(`^%sT\d{6}--daily.*\.md$`, todayDenote))
-	
-	for _, file := range files {
-		filename := filepath.Base(file)
-		if denotePattern.MatchString(filename) {
-			return file, nil
-		}
-	}
-	
-	// No daily note found for today
-	return "", os.ErrNotExist
-}
-
-// Search for files containing a specific tag using ripgrep
-func searchTag(dir, tag string) ([]string, error) {
-	// Remove # if present at the start
-	tag = strings.TrimPrefix(tag, "#")
-	
-	// We'll search for the tag in different contexts:
-	// 1. Inline hashtag: #tag
-	// 2. In YAML frontmatter tags field (various formats)
-	//    - tags: [tag1, tag2]
-	//    - tags: ["tag1", "tag2"]  
-	//    - tags:
-	//      - tag1
-	//      - tag2
-	
-	// Run ripgrep with multiple patterns
-	cmd := exec.Command("rg", "-l", "--type", "md", 
-		"-e", fmt.Sprintf("#%s\\b", tag),                    // #tag with word boundary
-		"-e", fmt.Sprintf(`tags:.*\b%s\b`, tag),            // tags: line containing the tag
-		"-e", fmt.Sprintf(`^\s*-\s+%s\b`, tag),             // - tag in YAML lists
-		dir)
-	
-	output, err := cmd.Output()
-	if err != nil {
-		// Check if it's just "no matches found" (exit code 1)
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			return []string{}, nil
-		}
-		return nil, err
-	}
-	
-	// Parse output - each line is a file path
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	var files []string
-	for _, line := range lines {
-		if line != "" {
-			files = append(files, line)
-		}
-	}
-	
-	return files, nil
-}
-
-// Search for files containing open tasks using ripgrep
-func searchTasks(dir string) ([]string, error) {
-	// Search for unchecked checkbox pattern: "- [ ]"
-	// Use -F for fixed strings and -- to stop flag parsing
-	cmd := exec.Command("rg", "-l", "--type", "md", "-F", "--", "- [ ]", dir)
-	output, err := cmd.Output()
-	if err != nil {
-		// Check if it's just "no matches found" (exit code 1)
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			return []string{}, nil
-		}
-		return nil, err
-	}
-	
-	// Parse output - each line is a file path
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	var files []string
-	for _, line := range lines {
-		if line != "" {
-			files = append(files, line)
-		}
-	}
-	
-	return files, nil
-}
-
-// Search for daily note files (matching *-daily.md pattern or Denote files with __daily tag)
-func searchDailyNotes(dir string) ([]string, error) {
-	// Get all markdown files first
-	allFiles, err := findMarkdownFiles(dir, Config{})
-	if err != nil {
-		return nil, err
-	}
-	
-	// Filter for files matching the daily note pattern
-	var dailyFiles []string
-	for _, file := range allFiles {
-		filename := filepath.Base(file)
-		// Check for traditional daily note format (*-daily.md)
-		if strings.HasSuffix(filename, "-daily.md") {
-			dailyFiles = append(dailyFiles, file)
-		} else if strings.Contains(filename, "__") && strings.Contains(filename, "_daily") {
-			// Check for Denote format with daily tag (e.g., 20250623T094530-daily__daily.md)
-			dailyFiles = append(dailyFiles, file)
-		}
-	}
-	
-	return dailyFiles, nil
+// Search for files containing open tasks using the note index
+func searchTasks(idx *NoteIndex, files []string) []string {
+	return idx.Select(files, func(entry *NoteEntry) bool {
+		return entry.OpenTasks > 0
+	})
 }
 
 // Sort files by different criteria
-func sortFilesByDate(files []string) []string {
+func sortFilesByDate(idx *NoteIndex, files []string) []string {
 	sorted := make([]string, len(files))
 	copy(sorted, files)
 	
@@ -993,28 +834,28 @@ func sortFilesByDate(files []string) []string {
 		}
 		
 		// Fall back to file modification time
-		statI, errI := os.Stat(sorted[i])
-		statJ, errJ := os.Stat(sorted[j])
-		if errI != nil || errJ != nil {
+		modI, okI := idx.ModTime(sorted[i])
+		modJ, okJ := idx.ModTime(sorted[j])
+		if !okI || !okJ {
 			return sorted[i] < sorted[j] // Fallback to name sort
 		}
-		return statI.ModTime().After(statJ.ModTime()) // Newer files first
+		return modI.After(modJ) // Newer files first
 	})
 	
 	return sorted
 }
 
-func sortFilesByModified(files []string) []string {
+func sortFilesByModified(idx *NoteIndex, files []string) []string {
 	sorted := make([]string, len(files))
 	copy(sorted, files)
 	
 	sort.Slice(sorted, func(i, j int) bool {
-		statI, errI := os.Stat(sorted[i])
-		statJ, errJ := os.Stat(sorted[j])
-		if errI != nil || errJ != nil {
+		modI, okI := idx.ModTime(sorted[i])
+		modJ, okJ := idx.ModTime(sorted[j])
+		if !okI || !okJ {
 			return sorted[i] < sorted[j] // Fallback to name sort
 		}
-		return statI.ModTime().After(statJ.ModTime()) // Newer files first
+		return modI.After(modJ) // Newer files first
 	})
 	
 	return sorted
@@ -1073,9 +914,9 @@ func (m *model) applySorting(files []string) []string {
 	
 	switch m.currentSort {
 	case "date":
-		sorted = sortFilesByDate(files)
+		sorted = sortFilesByDate(m.index, files)
 	case "modified":
-		sorted = sortFilesByModified(files)
+		sorted = sortFilesByModified(m.index, files)
 	case "title":
 		sorted = sortFilesByTitle(files)
 	case "denote":
@@ -1096,38 +937,84 @@ func (m *model) applySorting(files []string) []string {
 	return sorted
 }
 
+// refreshFiles rebuilds the sorted file list from the note index
+func (m *model) refreshFiles() {
+	m.files = m.applySorting(m.index.Paths())
+}
+
+// nextSyncDrift opens the sync dialog on the next queued note whose
+// filename and frontmatter disagree, or closes it when there are none left.
+// It reports whether one was found.
+func (m *model) nextSyncDrift() bool {
+	for len(m.syncQueue) > 0 {
+		path := m.syncQueue[0]
+		m.syncQueue = m.syncQueue[1:]
+		drift, err := checkDenoteSync(path, m.config.denoteOrder())
+		if err == nil && drift != nil && drift.Drifted() {
+			m.syncMode = true
+			m.syncDrift = drift
+			m.keepCursorOn(path)
+			return true
+		}
+	}
+	m.syncMode = false
+	m.syncDrift = nil
+	return false
+}
+
+// keepCursorOn moves the cursor to path if it is listed, then clamps it
+func (m *model) keepCursorOn(path string) {
+	if path != "" {
+		for i, f := range m.filtered {
+			if f == path {
+				m.cursor = i
+				break
+			}
+		}
+	}
+	
+	if m.cursor >= len(m.filtered) {
+		m.cursor = len(m.filtered) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
 // Filter files by days old (files modified within last N days)
-func filterFilesByDaysOld(files []string, days int) []string {
+func filterFilesByDaysOld(idx *NoteIndex, files []string, days int) []string {
 	if days <= 0 {
 		return files
 	}
 	
 	cutoff := time.Now().AddDate(0, 0, -days)
-	var filtered []string
-	
-	for _, file := range files {
-		if stat, err := os.Stat(file); err == nil {
-			if stat.ModTime().After(cutoff) {
-				filtered = append(filtered, file)
-			}
-		}
-	}
-	
-	return filtered
+	return idx.Select(files, func(entry *NoteEntry) bool {
+		return entry.ModTime.After(cutoff)
+	})
 }
 func (m model) Init() tea.Cmd {
-	return tea.WindowSize()
+	cmds := []tea.Cmd{tea.WindowSize(), m.watcher.Wait()}
+	if n := m.watcher.Polled(); n > 0 {
+		cmds = append(cmds, ui.ShowWarning(fmt.Sprintf("Can't watch %d folders for changes; checking them every %v instead", n, watchPollInterval)))
+	}
+	if m.startupError != "" {
+		cmds = append(cmds, ui.ShowError(m.startupError))
+	}
+	if m.startupCmd != nil {
+		cmds = append(cmds, m.startupCmd)
+	}
+	return tea.Batch(cmds...)
 }
 
 // Simple markdown renderer for fast preview
 // Load preview content for popover (with simple markdown rendering)
 func (m *model) loadPreviewForPopover() tea.Cmd {
-	if m.cursor >= len(m.filtered) || m.cursor < 0 {
+	if m.previewFile == "" {
 		return nil
 	}
 	
-	filepath := m.filtered[m.cursor]
-	width := m.width // Capture width for the closure
+	filepath := m.previewFile
+	opts := m.previewOptions(filepath, m.previewLink) // Capture options for the closure
 	
 	return func() tea.Msg {
 		content, err := os.ReadFile(filepath)
@@ -1138,18 +1025,33 @@ func (m *model) loadPreviewForPopover() tea.Cmd {
 			}
 		}
 		
-		// Use simple markdown renderer
-		popoverContentWidth := (width * 80 / 100) - 6
-		rendered := ui.RenderSimpleMarkdown(string(content), popoverContentWidth)
+		// Use simple markdown renderer, collecting links to follow
+		rendered, links := ui.RenderMarkdown(string(content), opts)
 		
 		return previewLoadedMsg{
 			content: rendered,
+			raw: string(content),
+			links: links,
 			filepath: filepath,
 		}
 	}
 }
 
+// Update handles a message, then keeps the split layout's preview pane on
+// the note under the cursor
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	next, ok := updated.(model)
+	if !ok {
+		return updated, cmd
+	}
+	if load := next.syncSplitPreview(); load != nil {
+		return next, tea.Batch(cmd, load)
+	}
+	return next, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -1161,6 +1063,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.ui != nil {
 			m.ui.UpdateSize(msg.Width, msg.Height)
 		}
+		m.splitFile = "" // Rerender the preview pane at the new width
 		// If in preview mode, reload with new dimensions
 		if m.previewMode {
 			return m, m.loadPreviewForPopover()
@@ -1168,7 +1071,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case previewLoadedMsg:
+		// Ignore content for a note the preview has already moved past
+		if msg.filepath != m.previewFile {
+			return m, nil
+		}
 		m.previewContent = msg.content
+		m.previewRaw = msg.raw
+		m.previewLinks = msg.links
+		
+		// Jump to the first content search hit
+		lines := strings.Split(m.previewContent, "\n")
+		if m.previewQuery != "" {
+			if hit := firstMatchingLine(lines, m.previewQuery); hit >= 0 {
+				m.previewScroll = hit
+			}
+			m.previewQuery = ""
+		}
+		
+		// Keep the scroll position inside the note, which may be shorter
+		// than the one it was remembered for
+		m.previewScroll = max(0, min(m.previewScroll, len(lines)-m.previewContentHeight()))
 		return m, nil
 
 	case ui.StatusMsg:
@@ -1183,99 +1105,112 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case splitPreviewMsg:
+		// Only the latest load may fill the pane
+		if msg.gen == m.splitGen {
+			m.splitShown = msg.file
+			m.splitContent = msg.content
+		}
+		return m, nil
+
 	case clearSelectedMsg:
 		// Remember the file that was just edited (if any)
 		previousFile := m.selected
 		m.selected = ""
 		
-		// Refresh file list after returning from editor
-		files, err := findMarkdownFiles(m.cwd, m.config)
-		if err != nil {
-			return m, nil
+		// The editor may have changed the note, so reparse it
+		if previousFile != "" {
+			m.index.Update(previousFile)
 		}
 		
-		// Apply current sort
-		m.files = m.applySorting(files)
+		// Apply current sort and reapply any active filters
+		m.refreshFiles()
+		m.applyActiveFilters()
 		
-		// Reapply any active filters
-		if m.taskFilter {
-			if taskFiles, err := searchTasks(m.cwd); err == nil {
-				m.filtered = taskFiles
-			}
-		} else if m.tagFilter && m.tagInput.Value() != "" {
-			if tagFiles, err := searchTag(m.cwd, m.tagInput.Value()); err == nil {
-				m.filtered = tagFiles
-			}
-		} else if m.textFilter && m.search.Value() != "" {
-			m.filtered = filterFiles(m.files, m.search.Value())
-		} else if m.dailyFilter {
-			if dailyFiles, err := searchDailyNotes(m.cwd); err == nil {
-				m.filtered = dailyFiles
-			}
-		} else if m.oldFilter {
-			m.filtered = filterFilesByDaysOld(m.files, m.oldDays)
-		} else {
-			// No filter active, use all files
-			m.filtered = m.files
+		// Try to maintain cursor position on the edited file
+		m.keepCursorOn(previousFile)
+		m.splitFile = "" // Reload the preview pane in case it changed
+		if m.taskMode {
+			m.refreshTasks()
 		}
 		
-		// Apply sorting to filtered list
-		m.filtered = m.applySorting(m.filtered)
-		
-		// Try to maintain cursor position on the edited file
-		if previousFile != "" {
-			for i, f := range m.filtered {
-				if f == previousFile {
-					m.cursor = i
-					break
-				}
-			}
+		return m, nil
+
+	case filesChangedMsg:
+		// Notes changed outside the app; remember what's under the cursor
+		var current string
+		if m.cursor < len(m.filtered) {
+			current = m.filtered[m.cursor]
 		}
 		
-		// Ensure cursor is within bounds
-		if m.cursor >= len(m.filtered) && len(m.filtered) > 0 {
-			m.cursor = len(m.filtered) - 1
+		for _, path := range msg.paths {
+			m.index.Refresh(path)
+		}
+		m.refreshFiles()
+		m.applyActiveFilters()
+		m.keepCursorOn(current)
+		m.splitFile = "" // Reload the preview pane in case it changed
+		if m.taskMode {
+			m.refreshTasks()
 		}
 		
-		return m, nil
+		// Keep listening for the next batch
+		return m, m.watcher.Wait()
 
 
 	case tea.KeyMsg:
+		// After a blocked undo, y drops that operation so older ones can be
+		// undone; any other key carries on as usual
+		if m.undoBlocked {
+			m.undoBlocked = false
+			if msg.String() == "y" {
+				op, err := m.journal.drop()
+				if err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Can't update undo history: %v", err))
+				}
+				return m, ui.ShowInfo("Dropped " + op.Label + " from the undo history")
+			}
+		}
+
 		// Handle modal input modes first (before main key handling)
 		if m.searchMode {
 			// In search mode, let the search input handle most keys
 			switch msg.String() {
 			case "esc":
-				// Exit search mode
+				// Exit search mode, leaving the filter stack as it was
 				m.searchMode = false
 				m.search.SetValue("")
-				m.filtered = m.files
+				m.contentSearch = false
+				m.applyActiveFilters()
+				m.cursor = 0
+				return m, nil
+			case "tab":
+				// Toggle between filename and content search
+				m.contentSearch = !m.contentSearch
+				m.applyActiveFilters()
 				m.cursor = 0
 				return m, nil
 			case "enter":
-				// Exit search mode on enter, but keep the filter active
+				// Compile the query; on a syntax error stay in search mode to fix it
+				query, err := parseQuery(m.search.Value(), m.contentSearch)
+				if err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Query error: %v", err))
+				}
+				// Exit search mode on enter, keeping the query's terms as filters
 				m.searchMode = false
-				// If there's a search query, mark text filter as active
-				if m.search.Value() != "" {
-					m.textFilter = true
-					m.taskFilter = false // Clear other filters
-					m.tagFilter = false
-					m.dailyFilter = false
-					m.oldFilter = false
+				m.search.SetValue("")
+				m.contentSearch = false
+				if len(query) > 0 {
+					m.pushFilter(query...)
+				} else {
+					m.applyActiveFilters()
 				}
 				return m, nil
 			default:
 				// Let the search input handle all other keys
 				m.search, cmd = m.search.Update(msg)
-				query := m.search.Value()
-				m.filtered = filterFiles(m.files, query)
+				m.applyActiveFilters()
 				m.cursor = 0 // Reset cursor when filtering
-				// Clear other filters when doing a text search
-				m.taskFilter = false
-				m.tagFilter = false
-				m.textFilter = false // Also clear text filter since we're in live search mode
-				m.dailyFilter = false
-				m.oldFilter = false
 				cmds = append(cmds, cmd)
 				return m, tea.Batch(cmds...)
 			}
@@ -1301,41 +1236,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.tagCreateMode = true
 						m.tagCreateInput.Focus()
 						return m, nil
-					} else {
-						// Create note without tags
-						var filename string
-						var identifier string
-						if m.config.DenoteFilenames {
-							filename, identifier = generateDenoteName(title, []string{}, time.Now())
-						} else {
-							filename = titleToFilename(title)
-							identifier = ""
-						}
-						fullPath := filepath.Join(m.cwd, filename)
-						
-						// Create the file with templated content
-						content := generateNoteContent(title, m.config, identifier, nil)
-						if err := os.WriteFile(fullPath, []byte(content), 0644); err == nil {
-							m.selected = fullPath
-							// Refresh file list to include new file
-							files, _ := findMarkdownFiles(m.cwd, m.config)
-							m.files = m.applySorting(files)
-							m.filtered = m.files
-							// Find and select the new file
-							for i, f := range m.filtered {
-								if f == fullPath {
-									m.cursor = i
-									break
-								}
-							}
-							// Exit create mode and open editor
-							m.createMode = false
-							m.createInput.SetValue("")
-							return m, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
-								return clearSelectedMsg{}
-							})
-						}
 					}
+					// Create note without tags
+					m.createMode = false
+					m.createInput.SetValue("")
+					return m, m.finishCreate(title, nil)
 				}
 				// Exit create mode if title is empty
 				m.createMode = false
@@ -1355,22 +1260,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Exit tag mode
 				m.tagMode = false
 				m.tagInput.SetValue("")
-				m.filtered = m.files
-				m.cursor = 0
 				return m, nil
 			case "enter":
 				// Search for the tag
 				tag := m.tagInput.Value()
+				tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
 				if tag != "" {
-					if files, err := searchTag(m.cwd, tag); err == nil {
-						m.filtered = files
-						m.cursor = 0
-						m.tagFilter = true // Set tag filter active
-						m.taskFilter = false // Clear task filter when switching to tag filter
-						m.textFilter = false // Clear text filter when switching to tag filter
-						m.dailyFilter = false // Clear daily filter when switching to tag filter
-						m.oldFilter = false // Clear old filter when switching to tag filter
-					}
+					// Narrow the current results to notes with this tag
+					m.pushFilter(noteFilter{Kind: filterTag, Value: tag})
 				}
 				// Exit tag mode
 				m.tagMode = false
@@ -1389,44 +1286,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "esc":
 				// Exit tag create mode and create note without tags
 				title := m.pendingTitle
-				var filename string
-				var identifier string
-				if m.config.DenoteFilenames {
-					filename, identifier = generateDenoteName(title, []string{}, time.Now())
-				} else {
-					filename = titleToFilename(title)
-					identifier = ""
-				}
-				fullPath := filepath.Join(m.cwd, filename)
-				
-				// Create the file without tags
-				content := generateNoteContent(title, m.config, identifier, nil)
-				if err := os.WriteFile(fullPath, []byte(content), 0644); err == nil {
-					m.selected = fullPath
-					// Refresh file list to include new file
-					files, _ := findMarkdownFiles(m.cwd, m.config)
-					m.files = m.applySorting(files)
-					m.filtered = m.files
-					// Find and select the new file
-					for i, f := range m.filtered {
-						if f == fullPath {
-							m.cursor = i
-							break
-						}
-					}
-					// Exit tag create mode and open editor
-					m.tagCreateMode = false
-					m.tagCreateInput.SetValue("")
-					m.pendingTitle = ""
-					return m, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
-						return clearSelectedMsg{}
-					})
-				}
-				// If file creation failed, still exit the mode
 				m.tagCreateMode = false
 				m.tagCreateInput.SetValue("")
 				m.pendingTitle = ""
-				return m, nil
+				return m, m.finishCreate(title, nil)
 			case "enter":
 				// Process tags and create the note
 				tagInput := m.tagCreateInput.Value()
@@ -1444,143 +1307,575 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				
 				// Now create the note with the pending title and tags
 				title := m.pendingTitle
-				var filename string
-				var identifier string
-				if m.config.DenoteFilenames {
-					filename, identifier = generateDenoteName(title, tags, time.Now())
-				} else {
-					filename = titleToFilename(title)
-					identifier = ""
+				m.tagCreateMode = false
+				m.tagCreateInput.SetValue("")
+				m.pendingTitle = ""
+				return m, m.finishCreate(title, tags)
+			default:
+				// Let the tag create input handle all other keys
+				m.tagCreateInput, cmd = m.tagCreateInput.Update(msg)
+				cmds = append(cmds, cmd)
+				return m, tea.Batch(cmds...)
+			}
+		}
+
+		if m.templateMode {
+			switch msg.String() {
+			case "esc", "q":
+				// Cancel creating the note
+				m.templateMode = false
+				m.templates = nil
+				m.pendingTitle = ""
+				m.pendingTags = nil
+				return m, nil
+			case "up", "k":
+				if m.templateCursor > 0 {
+					m.templateCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.templateCursor < len(m.templates) {
+					m.templateCursor++
+				}
+				return m, nil
+			case "enter":
+				// Create the note from the chosen template, or blank
+				var tmpl *noteTemplate
+				if m.templateCursor > 0 && m.templateCursor <= len(m.templates) {
+					tmpl = &m.templates[m.templateCursor-1]
+				}
+				title, tags := m.pendingTitle, m.pendingTags
+				m.templateMode = false
+				m.templates = nil
+				m.pendingTitle = ""
+				m.pendingTags = nil
+				return m, m.createNote(title, tags, tmpl)
+			}
+			return m, nil
+		}
+
+		if m.oldMode {
+			switch msg.String() {
+			case "esc":
+				// Exit old mode
+				m.oldMode = false
+				m.oldInput.SetValue("")
+				return m, nil
+			case "enter":
+				// Apply days old filter
+				daysStr := m.oldInput.Value()
+				if daysStr != "" {
+					if days, err := strconv.Atoi(daysStr); err == nil && days > 0 {
+						// Narrow the current results to recently modified notes
+						m.pushFilter(noteFilter{Kind: filterOld, Days: days})
+					}
+				}
+				// Exit old mode
+				m.oldMode = false
+				m.oldInput.SetValue("")
+				return m, nil
+			default:
+				// Let the old input handle all other keys
+				m.oldInput, cmd = m.oldInput.Update(msg)
+				cmds = append(cmds, cmd)
+				return m, tea.Batch(cmds...)
+			}
+		}
+
+		// Handle saved view picker
+		if m.viewMode {
+			switch msg.String() {
+			case "esc", "q":
+				// Exit view picker
+				m.viewMode = false
+				return m, nil
+			case "up", "k":
+				if m.viewCursor > 0 {
+					m.viewCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.viewCursor < len(m.config.Views)-1 {
+					m.viewCursor++
+				}
+				return m, nil
+			case "enter":
+				// Replace the current filters with the chosen view
+				m.viewMode = false
+				if m.viewCursor < len(m.config.Views) {
+					if err := m.applyView(m.config.Views[m.viewCursor]); err != nil {
+						return m, ui.ShowError(err.Error())
+					}
+				}
+				return m, nil
+			}
+			return m, nil
+		}
+
+		// Handle preview mode separately
+		if m.previewMode {
+			switch msg.String() {
+			case "esc", "q":
+				m.closePreview()
+				m.selected = "" // Clear selected file to prevent editor opening on quit
+				return m, nil
+			
+			case "e", "ctrl+e":
+				// Open in editor from preview
+				m.selected = m.previewFile
+				m.closePreview()
+				return m, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
+					return clearSelectedMsg{}
+				})
+			
+			case "tab":
+				// Highlight the next link
+				m.cyclePreviewLink(1)
+				return m, nil
+			
+			case "shift+tab":
+				// Highlight the previous link
+				m.cyclePreviewLink(-1)
+				return m, nil
+			
+			case "enter":
+				// Open the highlighted link's note
+				return m, m.followPreviewLink()
+			
+			case "backspace":
+				// Return to the note the last link was followed from
+				return m, m.previewBack()
+			
+			case "up", "k":
+				if m.previewScroll > 0 {
+					m.previewScroll--
+				}
+				return m, nil
+			
+			case "down", "j":
+				// Check if we can scroll down
+				lines := strings.Split(m.previewContent, "\n")
+				contentHeight := m.previewContentHeight()
+				if m.previewScroll < len(lines)-contentHeight {
+					m.previewScroll++
+				}
+				return m, nil
+			
+			case "pgup":
+				contentHeight := m.previewContentHeight()
+				m.previewScroll -= contentHeight
+				if m.previewScroll < 0 {
+					m.previewScroll = 0
+				}
+				return m, nil
+				
+			case "pgdown", " ": // Space bar also pages down
+				lines := strings.Split(m.previewContent, "\n")
+				contentHeight := m.previewContentHeight()
+				m.previewScroll += contentHeight
+				maxScroll := len(lines) - contentHeight
+				if m.previewScroll > maxScroll {
+					m.previewScroll = maxScroll
+				}
+				if m.previewScroll < 0 {
+					m.previewScroll = 0
+				}
+				return m, nil
+			}
+			return m, nil
+		}
+
+		// Handle backlinks panel; the preview above takes over while open
+		if m.backlinksMode {
+			sources, _ := backlinkSources(m.index.Backlinks(m.backlinkTarget))
+			switch msg.String() {
+			case "esc", "q", "b":
+				// Close backlinks panel
+				m.backlinksMode = false
+				m.backlinkTarget = ""
+				return m, nil
+			case "up", "k":
+				if m.backlinkCursor > 0 {
+					m.backlinkCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.backlinkCursor < len(sources)-1 {
+					m.backlinkCursor++
+				}
+				return m, nil
+			case "enter":
+				// Preview the linking note, scrolled to the link
+				if m.backlinkCursor < len(sources) {
+					m.openPreview(sources[m.backlinkCursor], 0, -1)
+					m.previewMode = true
+					m.previewQuery = noteLinkName(m.index, m.backlinkTarget)
+					return m, m.loadPreviewForPopover()
+				}
+				return m, nil
+			case "e", "ctrl+e":
+				// Edit the linking note
+				if m.backlinkCursor < len(sources) {
+					m.selected = sources[m.backlinkCursor]
+					return m, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
+						return clearSelectedMsg{}
+					})
+				}
+				return m, nil
+			}
+			return m, nil
+		}
+
+		// Handle task view tag input
+		if m.taskTagMode {
+			switch msg.String() {
+			case "esc":
+				m.taskTagMode = false
+				m.taskTagInput.SetValue(m.taskTag)
+				return m, nil
+			case "enter":
+				// Empty input shows tasks from every note again
+				m.taskTagMode = false
+				m.taskTag = strings.TrimPrefix(strings.TrimSpace(m.taskTagInput.Value()), "#")
+				m.taskCursor = 0
+				m.refreshTasks()
+				return m, nil
+			default:
+				m.taskTagInput, cmd = m.taskTagInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		// Handle the metadata editor
+		if m.metaMode {
+			// Typing a value, tag or key
+			if m.metaEdit != "" {
+				switch msg.String() {
+				case "esc":
+					m.closeMetaInput()
+				case "enter":
+					m.commitMetaInput()
+				case "tab":
+					if m.metaEdit == "tag" {
+						m.completeMetaTag()
+					}
+				default:
+					m.metaCompletions = nil
+					m.metaInput, cmd = m.metaInput.Update(msg)
+					return m, cmd
+				}
+				return m, nil
+			}
+			
+			switch msg.String() {
+			case "esc", "q":
+				// Close without saving
+				m.metaMode = false
+				m.metaFields = nil
+				return m, nil
+			case "up", "k":
+				if m.metaCursor > 0 {
+					m.metaCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.metaCursor < len(m.metaFields)-1 {
+					m.metaCursor++
+				}
+				return m, nil
+			case "enter", "e":
+				// Edit the value under the cursor; tags are removed and re-added
+				if m.metaCursor < len(m.metaFields) {
+					field := m.metaFields[m.metaCursor]
+					if field.Tag {
+						m.startMetaInput("tag", "")
+					} else if !field.ReadOnly {
+						m.startMetaInput("value", field.Value)
+					}
+				}
+				return m, nil
+			case "a", "#":
+				m.startMetaInput("tag", "")
+				return m, nil
+			case "n":
+				m.startMetaInput("key", "")
+				return m, nil
+			case "x", "d":
+				m.removeMetaField()
+				return m, nil
+			case "s", "ctrl+s":
+				// Write the frontmatter back and close
+				op := newOperation("metadata of " + filepath.Base(m.metaFile))
+				if err := op.edit(m.metaFile); err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Error saving metadata: %v", err))
+				}
+				if err := saveMetadata(m.index, m.metaFile, m.metaFields); err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Error saving metadata: %v", err))
+				}
+				m.metaMode = false
+				m.metaFields = nil
+				m.refreshFiles()
+				m.applyActiveFilters()
+				m.keepCursorOn(m.metaFile)
+				return m, m.record(op, ui.ShowSuccess("Saved metadata"))
+			}
+			return m, nil
+		}
+
+		// Handle the Denote sync dialog
+		if m.syncMode {
+			drift := m.syncDrift
+			switch msg.String() {
+			case "esc", "q":
+				m.syncMode = false
+				m.syncDrift = nil
+				m.syncQueue = nil
+				return m, nil
+			case "s":
+				// Leave this note and move on to the next drifted one
+				if !m.nextSyncDrift() {
+					return m, ui.ShowInfo("No more drifted notes")
+				}
+				return m, nil
+			case "f", "n":
+				// Frontmatter wins: rename the file, keeping its identifier.
+				// Filename wins: rewrite the frontmatter.
+				op := newOperation("sync of " + filepath.Base(drift.Path))
+				newPath, err := syncNote(m.index, op, drift, msg.String() == "f")
+				m.refreshFiles()
+				m.applyActiveFilters()
+				if err != nil {
+					m.syncMode = false
+					m.syncDrift = nil
+					m.syncQueue = nil
+					return m, m.record(op, ui.ShowError(fmt.Sprintf("Error syncing: %v", err)))
+				}
+				m.keepCursorOn(newPath)
+				status := ui.ShowSuccess("Updated frontmatter from the filename")
+				if newPath != drift.Path {
+					status = ui.ShowSuccess("Renamed to " + filepath.Base(newPath))
 				}
-				fullPath := filepath.Join(m.cwd, filename)
-				
-				// Create the file with templated content including tags
-				content := generateNoteContent(title, m.config, identifier, tags)
-				if err := os.WriteFile(fullPath, []byte(content), 0644); err == nil {
-					m.selected = fullPath
-					// Refresh file list to include new file
-					files, _ := findMarkdownFiles(m.cwd, m.config)
-					m.files = m.applySorting(files)
-					m.filtered = m.files
-					// Find and select the new file
-					for i, f := range m.filtered {
-						if f == fullPath {
-							m.cursor = i
-							break
-						}
+				m.nextSyncDrift()
+				return m, m.record(op, status)
+			case "F", "N":
+				// Resolve this note and every drifted one after it the same
+				// way, as one operation
+				op := newOperation("sync")
+				synced, failed := 0, 0
+				for drift != nil {
+					if _, err := syncNote(m.index, op, drift, msg.String() == "F"); err != nil {
+						failed++
+					} else {
+						synced++
+					}
+					drift = nil
+					if m.nextSyncDrift() {
+						drift = m.syncDrift
 					}
-					// Exit tag create mode and open editor
-					m.tagCreateMode = false
-					m.tagCreateInput.SetValue("")
-					m.pendingTitle = ""
-					return m, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
-						return clearSelectedMsg{}
-					})
 				}
-				return m, nil
-			default:
-				// Let the tag create input handle all other keys
-				m.tagCreateInput, cmd = m.tagCreateInput.Update(msg)
-				cmds = append(cmds, cmd)
-				return m, tea.Batch(cmds...)
+				op.Label = fmt.Sprintf("sync of %d notes", synced)
+				m.refreshFiles()
+				m.applyActiveFilters()
+				if failed > 0 {
+					return m, m.record(op, ui.ShowError(fmt.Sprintf("Synced %d notes; %d couldn't be synced", synced, failed)))
+				}
+				return m, m.record(op, ui.ShowSuccess(fmt.Sprintf("Synced %d notes", synced)))
 			}
+			return m, nil
 		}
 
-		if m.oldMode {
+		// Handle the trash browser
+		if m.trashMode {
+			if m.trashPurge {
+				// Anything but y cancels a permanent delete
+				m.trashPurge = false
+				if msg.String() != "y" || m.trashCursor >= len(m.trashItems) {
+					return m, nil
+				}
+				item := m.trashItems[m.trashCursor]
+				if err := purgeTrash(item); err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Error deleting %s: %v", filepath.Base(item.Original), err))
+				}
+				m.loadTrash()
+				return m, ui.ShowSuccess(fmt.Sprintf("Deleted %s permanently", filepath.Base(item.Original)))
+			}
 			switch msg.String() {
-			case "esc":
-				// Exit old mode
-				m.oldMode = false
-				m.oldInput.SetValue("")
+			case "esc", "q", "U":
+				m.trashMode = false
+				m.trashItems = nil
 				return m, nil
-			case "enter":
-				// Apply days old filter
-				daysStr := m.oldInput.Value()
-				if daysStr != "" {
-					if days, err := strconv.Atoi(daysStr); err == nil && days > 0 {
-						filteredFiles := filterFilesByDaysOld(m.files, days)
-						m.filtered = filteredFiles
-						m.cursor = 0
-						m.oldFilter = true
-						m.oldDays = days
-						// Clear other filters when switching to old filter
-						m.taskFilter = false
-						m.tagFilter = false
-						m.textFilter = false
-						m.dailyFilter = false
-					}
+			case "up", "k":
+				if m.trashCursor > 0 {
+					m.trashCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.trashCursor < len(m.trashItems)-1 {
+					m.trashCursor++
+				}
+				return m, nil
+			case "enter", "r":
+				// Put the note back where it was deleted from
+				if m.trashCursor >= len(m.trashItems) {
+					return m, nil
+				}
+				item := m.trashItems[m.trashCursor]
+				if err := restoreFromTrash(item.Path, item.Original); err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Error restoring %s: %v", filepath.Base(item.Original), err))
+				}
+				// Undoing a restore puts the note back in the trash
+				op := newOperation("restore of " + filepath.Base(item.Original))
+				op.created(item.Original)
+				m.index.Refresh(item.Original)
+				m.refreshFiles()
+				m.applyActiveFilters()
+				m.keepCursorOn(item.Original)
+				m.loadTrash()
+				return m, m.record(op, ui.ShowSuccess("Restored "+filepath.Base(item.Original)))
+			case "x", "d":
+				if m.trashCursor < len(m.trashItems) {
+					m.trashPurge = true
 				}
-				// Exit old mode
-				m.oldMode = false
-				m.oldInput.SetValue("")
 				return m, nil
+			}
+			return m, nil
+		}
+
+		// Handle periodic note filter menu
+		if m.periodMode {
+			switch msg.String() {
+			case "esc", "P":
+				m.periodMode = false
 			default:
-				// Let the old input handle all other keys
-				m.oldInput, cmd = m.oldInput.Update(msg)
-				cmds = append(cmds, cmd)
-				return m, tea.Batch(cmds...)
+				// Narrow the current results to the chosen kind, picked by
+				// its configured key or the first letter of its name
+				periods := notePeriods(m.config)
+				chosen := slices.IndexFunc(periods, func(p notePeriod) bool { return p.Key == msg.String() })
+				if chosen < 0 {
+					chosen = slices.IndexFunc(periods, func(p notePeriod) bool { return p.Name[:1] == msg.String() })
+				}
+				if chosen >= 0 {
+					m.periodMode = false
+					m.pushFilter(noteFilter{Kind: filterPeriod, Value: periods[chosen].Name})
+				}
 			}
+			return m, nil
 		}
 
-		// Handle preview mode separately
-		if m.previewMode {
+		// Handle calendar
+		if m.calendarMode {
 			switch msg.String() {
-			case "esc", "q":
-				m.previewMode = false
-				m.previewContent = ""
-				m.previewScroll = 0
-				m.selected = "" // Clear selected file to prevent editor opening on quit
+			case "esc", "q", "C":
+				m.calendarMode = false
+			case "h", "left":
+				m.moveCalendar(-1, 0)
+			case "l", "right":
+				m.moveCalendar(1, 0)
+			case "k", "up":
+				m.moveCalendar(-7, 0)
+			case "j", "down":
+				m.moveCalendar(7, 0)
+			case "H", "pgup":
+				m.moveCalendar(0, -1)
+			case "L", "pgdown":
+				m.moveCalendar(0, 1)
+			case "t":
+				// Back to today
+				m.openCalendar()
+			case "enter", "e":
+				// Open or create the daily note for the chosen day
+				m.calendarMode = false
+				return m, m.openDailyNote(m.calendarDay)
+			}
+			return m, nil
+		}
+
+		// Handle task view
+		if m.taskMode {
+			switch msg.String() {
+			case "esc", "q", "T":
+				m.taskMode = false
+				m.tasks = nil
 				return m, nil
-			
-			case "e", "ctrl+e":
-				// Open in editor from preview
-				m.previewMode = false
-				m.previewContent = ""
-				m.previewScroll = 0
-				m.selected = m.previewFile
-				return m, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
-					return clearSelectedMsg{}
-				})
-			
 			case "up", "k":
-				if m.previewScroll > 0 {
-					m.previewScroll--
+				if m.taskCursor > 0 {
+					m.taskCursor--
 				}
 				return m, nil
-			
 			case "down", "j":
-				// Check if we can scroll down
-				lines := strings.Split(m.previewContent, "\n")
-				contentHeight := (m.height * 80 / 100) - 8
-				if m.previewScroll < len(lines)-contentHeight {
-					m.previewScroll++
+				if m.taskCursor < len(m.tasks)-1 {
+					m.taskCursor++
 				}
 				return m, nil
-			
-			case "pgup":
-				contentHeight := (m.height * 80 / 100) - 8
-				m.previewScroll -= contentHeight
-				if m.previewScroll < 0 {
-					m.previewScroll = 0
+			case " ", "x":
+				// Check or uncheck the task in its note
+				if m.taskCursor < len(m.tasks) {
+					task := m.tasks[m.taskCursor]
+					op := newOperation("task in " + filepath.Base(task.Path))
+					if err := op.edit(task.Path); err != nil {
+						return m, ui.ShowError(fmt.Sprintf("Error updating task: %v", err))
+					}
+					if err := toggleTask(m.index, task); err != nil {
+						return m, ui.ShowError(fmt.Sprintf("Error updating task: %v", err))
+					}
+					m.refreshFiles()
+					m.applyActiveFilters()
+					m.refreshTasks()
+					return m, m.record(op, nil)
 				}
 				return m, nil
-				
-			case "pgdown", " ": // Space bar also pages down
-				lines := strings.Split(m.previewContent, "\n")
-				contentHeight := (m.height * 80 / 100) - 8
-				m.previewScroll += contentHeight
-				maxScroll := len(lines) - contentHeight
-				if m.previewScroll > maxScroll {
-					m.previewScroll = maxScroll
+			case "a":
+				// Show or hide completed tasks
+				m.taskShowDone = !m.taskShowDone
+				m.refreshTasks()
+				return m, nil
+			case "tab":
+				// Switch between the plain list and the agenda
+				m.taskAgenda = !m.taskAgenda
+				m.taskCursor = 0
+				m.refreshTasks()
+				return m, nil
+			case "s":
+				// Cycle the sort: file order, due date, priority
+				switch m.taskSort {
+				case "":
+					m.taskSort = "due"
+				case "due":
+					m.taskSort = "priority"
+				default:
+					m.taskSort = ""
 				}
-				if m.previewScroll < 0 {
-					m.previewScroll = 0
+				m.taskCursor = 0
+				m.refreshTasks()
+				return m, nil
+			case "#":
+				// Limit tasks to notes with a tag
+				m.taskTagMode = true
+				m.taskTagInput.SetValue(m.taskTag)
+				m.taskTagInput.CursorEnd()
+				m.taskTagInput.Focus()
+				return m, nil
+			case "enter", "e", "ctrl+e":
+				// Edit the note at the task's line
+				if m.taskCursor < len(m.tasks) {
+					task := m.tasks[m.taskCursor]
+					m.selected = task.Path
+					return m, tea.ExecProcess(m.openInEditorAt(task.Line), func(err error) tea.Msg {
+						return clearSelectedMsg{}
+					})
 				}
 				return m, nil
 			}
 			return m, nil
 		}
 
+		// Periodic notes open on their keys (d, W, M and Q unless configured)
+		if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode && !m.renameMode {
+			if period, ok := m.periodForKey(msg.String()); ok {
+				return m, m.openPeriodicNote(period, time.Now())
+			}
+		}
+
 		// Normal mode key handling
 		switch msg.String() {
 		case "ctrl+c", "q":
@@ -1588,7 +1883,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Exit search mode on q
 				m.searchMode = false
 				m.search.SetValue("")
-				m.filtered = m.files
+				m.applyActiveFilters()
 				m.cursor = 0
 				return m, nil
 			}
@@ -1609,8 +1904,6 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Exit tag mode on q
 				m.tagMode = false
 				m.tagInput.SetValue("")
-				m.filtered = m.files
-				m.cursor = 0
 				return m, nil
 			}
 			if m.deleteMode {
@@ -1639,7 +1932,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Exit search mode
 				m.searchMode = false
 				m.search.SetValue("")
-				m.filtered = m.files
+				m.applyActiveFilters()
 				m.cursor = 0
 			}
 			if m.createMode {
@@ -1647,53 +1940,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.createMode = false
 				m.createInput.SetValue("")
 			}
-			if m.tagCreateMode {
-				// Exit tag create mode and create note without tags
-				title := m.pendingTitle
-				var filename string
-				var identifier string
-				if m.config.DenoteFilenames {
-					filename, identifier = generateDenoteName(title, []string{}, time.Now())
-				} else {
-					filename = titleToFilename(title)
-					identifier = ""
-				}
-				fullPath := filepath.Join(m.cwd, filename)
-				
-				// Create the file without tags
-				content := generateNoteContent(title, m.config, identifier, nil)
-				if err := os.WriteFile(fullPath, []byte(content), 0644); err == nil {
-					m.selected = fullPath
-					// Refresh file list to include new file
-					files, _ := findMarkdownFiles(m.cwd, m.config)
-					m.files = m.applySorting(files)
-					m.filtered = m.files
-					// Find and select the new file
-					for i, f := range m.filtered {
-						if f == fullPath {
-							m.cursor = i
-							break
-						}
-					}
-					// Exit tag create mode and open editor
-					m.tagCreateMode = false
-					m.tagCreateInput.SetValue("")
-					m.pendingTitle = ""
-					return m, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
-						return clearSelectedMsg{}
-					})
-				}
-				// If file creation failed, still exit the mode
-				m.tagCreateMode = false
-				m.tagCreateInput.SetValue("")
-				m.pendingTitle = ""
-			}
 			if m.tagMode {
 				// Exit tag mode
 				m.tagMode = false
 				m.tagInput.SetValue("")
-				m.filtered = m.files
-				m.cursor = 0
 			}
 			if m.deleteMode {
 				// Exit delete mode
@@ -1709,35 +1959,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.oldMode = false
 				m.oldInput.SetValue("")
 			}
-						if m.taskFilter {
-				// Clear task filter
-				m.taskFilter = false
-				m.filtered = m.files
-				m.cursor = 0
-			}
-			if m.tagFilter {
-				// Clear tag filter
-				m.tagFilter = false
-				m.filtered = m.files
-				m.cursor = 0
-			}
-			if m.textFilter {
-				// Clear text filter
-				m.textFilter = false
-				m.filtered = m.files
-				m.cursor = 0
-			}
-			if m.dailyFilter {
-				// Clear daily filter
-				m.dailyFilter = false
-				m.filtered = m.files
-				m.cursor = 0
-			}
-			if m.oldFilter {
-				// Clear old filter
-				m.oldFilter = false
-				m.filtered = m.files
-				m.cursor = 0
+			if len(m.filters) > 0 {
+				// Clear every active filter
+				m.clearFilters()
 			}
 			if m.renameMode {
 				// Exit rename mode
@@ -1745,6 +1969,60 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.renameFile = ""
 			}
 
+		case "v":
+			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode {
+				// Enter saved view picker
+				if len(m.config.Views) == 0 {
+					return m, ui.ShowInfo("No saved views; add [[views]] to config.toml")
+				}
+				m.viewMode = true
+				m.viewCursor = 0
+				for i, view := range m.config.Views {
+					if view.Name == m.activeView {
+						m.viewCursor = i
+					}
+				}
+				return m, nil
+			}
+
+		case "b":
+			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode && m.cursor < len(m.filtered) {
+				// Show notes linking to the selected note
+				m.backlinksMode = true
+				m.backlinkTarget = m.filtered[m.cursor]
+				m.backlinkCursor = 0
+				return m, nil
+			}
+
+		case "T":
+			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode {
+				// List every task across notes
+				m.taskMode = true
+				m.taskCursor = 0
+				m.refreshTasks()
+				return m, nil
+			}
+
+		case "P":
+			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode {
+				// Choose a kind of periodic note to list
+				m.periodMode = true
+				return m, nil
+			}
+
+		case "C":
+			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode {
+				// Pick a day's daily note from the calendar
+				m.openCalendar()
+				return m, nil
+			}
+
+		case "backspace":
+			if !m.deleteMode && !m.sortMode {
+				// Drop the most recently applied filter
+				m.popFilter()
+			}
+
 		case "n":
 			if m.deleteMode {
 				// Cancel deletion
@@ -1759,8 +2037,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "/":
 			if !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode {
-				// Enter search mode
+				// Enter search mode, starting with filename search
 				m.searchMode = true
+				m.contentSearch = false
 				m.search.Focus()
 				return m, nil
 			}
@@ -1775,16 +2054,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "D":
 			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode {
-				// Search for daily notes
-				if files, err := searchDailyNotes(m.cwd); err == nil {
-					m.filtered = files
-					m.cursor = 0
-					m.dailyFilter = true
-					m.taskFilter = false // Clear task filter when switching to daily filter
-					m.tagFilter = false // Clear tag filter when switching to daily filter
-					m.textFilter = false // Clear text filter when switching to daily filter
-					m.oldFilter = false // Clear old filter when switching to daily filter
-				}
+				// Narrow the current results to daily notes
+				m.pushFilter(noteFilter{Kind: filterPeriod, Value: "daily"})
 			}
 
 		case "X":
@@ -1808,6 +2079,77 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+		case "E":
+			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode && m.cursor < len(m.filtered) {
+				// Edit the selected note's frontmatter
+				if err := m.openMetadataEditor(m.filtered[m.cursor]); err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Can't edit metadata: %v", err))
+				}
+				return m, nil
+			}
+
+		case "S":
+			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode && m.cursor < len(m.filtered) {
+				// Compare Denote filenames with their frontmatter, from the
+				// selected note on through the rest of the list
+				drift, err := checkDenoteSync(m.filtered[m.cursor], m.config.denoteOrder())
+				if err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Can't check sync: %v", err))
+				}
+				m.syncQueue = append(slices.Clone(m.filtered[m.cursor+1:]), m.filtered[:m.cursor]...)
+				if drift != nil && drift.Drifted() {
+					m.syncMode = true
+					m.syncDrift = drift
+					return m, nil
+				}
+				if !m.nextSyncDrift() {
+					return m, ui.ShowInfo("Every note's filename and frontmatter agree")
+				}
+				return m, nil
+			}
+
+		case "u":
+			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode {
+				// Undo the last delete, rename, create or edit, including
+				// any made by sync or migrate since the journal was read
+				if err := m.journal.load(); err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Can't read undo history: %v", err))
+				}
+				if len(m.journal.ops) == 0 {
+					return m, ui.ShowInfo("Nothing to undo")
+				}
+				op, touched, err := m.journal.undo(m.cwd)
+				for _, path := range touched {
+					m.index.Refresh(path)
+				}
+				if len(touched) > 0 {
+					m.refreshFiles()
+					m.applyActiveFilters()
+					m.keepCursorOn(touched[len(touched)-1])
+				}
+				switch {
+				case op == nil:
+					// Until it is dropped, this operation blocks every older one
+					m.undoBlocked = true
+					blocked := m.journal.ops[len(m.journal.ops)-1]
+					return m, ui.ShowError(fmt.Sprintf("Can't undo %s: %v. Press y to drop it and undo older changes", blocked.Label, err))
+				case err != nil:
+					return m, ui.ShowError(fmt.Sprintf("Problem undoing %s: %v", op.Label, err))
+				}
+				return m, ui.ShowSuccess("Undid " + op.Label)
+			}
+
+		case "U":
+			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode {
+				// Browse deleted notes
+				m.trashCursor = 0
+				if err := m.loadTrash(); err != nil {
+					return m, ui.ShowError(fmt.Sprintf("Can't read the trash: %v", err))
+				}
+				m.trashMode = true
+				return m, nil
+			}
+
 		case "R":
 			if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode && m.cursor < len(m.filtered) {
 				// Enter rename mode - rename file to Denote format
@@ -1816,10 +2158,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				
 				// Perform the rename immediately
 				if newPath, err := renameToDenoteName(m.renameFile, m.config); err == nil {
-					// Refresh file list after successful rename
-					files, _ := findMarkdownFiles(m.cwd, m.config)
-					m.files = m.applySorting(files)
-					m.filtered = m.files
+					op := newOperation("rename of " + filepath.Base(m.renameFile))
+					op.renamed(m.renameFile, newPath)
+					cmds = append(cmds, m.record(op, nil))
+					
+					// Move the entry in the index and refresh the list
+					m.index.Rename(m.renameFile, newPath)
+					m.refreshFiles()
+					m.applyActiveFilters()
 					
 					// Try to maintain cursor position on the renamed file
 					for i, f := range m.filtered {
@@ -1843,57 +2189,6 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.filtered = m.applySorting(m.filtered)
 				m.sortMode = false
 				m.cursor = 0
-			} else if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode {
-				// First, check if a daily note already exists for today
-				if existingDaily, err := findTodaysDailyNote(m.cwd); err == nil {
-					// Daily note exists, open it
-					m.selected = existingDaily
-					// Find and select the file in the list
-					for i, f := range m.filtered {
-						if f == existingDaily {
-							m.cursor = i
-							break
-						}
-					}
-					return m, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
-						return clearSelectedMsg{}
-					})
-				} else {
-					// No daily note exists, create one in the current format
-					filename, identifier := getDailyNoteFilename(m.config)
-					fullPath := filepath.Join(m.cwd, filename)
-					
-					// Create the daily note with proper formatting
-					today := time.Now().Format("Monday, January 2, 2006")
-					title := fmt.Sprintf("Daily Note - %s", today)
-					
-					// Generate content with proper format
-					// For daily notes, we pass the "daily" tag automatically
-					var tags []string
-					if m.config.DenoteFilenames {
-						tags = []string{"daily"}
-					}
-					content := generateNoteContent(title, m.config, identifier, tags)
-					// Add daily note sections after frontmatter/title
-					content += "## Tasks\n\n## Notes\n\n"
-					if err := os.WriteFile(fullPath, []byte(content), 0644); err == nil {
-						m.selected = fullPath
-						// Refresh file list to include new file
-						files, _ := findMarkdownFiles(m.cwd, m.config)
-						m.files = m.applySorting(files)
-						m.filtered = m.files
-						// Find and select the new file
-						for i, f := range m.filtered {
-							if f == fullPath {
-								m.cursor = i
-								break
-							}
-						}
-						return m, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
-							return clearSelectedMsg{}
-						})
-					}
-				}
 			}
 
 		case "m":
@@ -1915,16 +2210,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.sortMode = false
 				m.cursor = 0
 			} else if !m.searchMode && !m.createMode && !m.tagMode && !m.tagCreateMode && !m.deleteMode && !m.sortMode && !m.oldMode {
-				// Search for tasks (existing functionality)
-				if files, err := searchTasks(m.cwd); err == nil {
-					m.filtered = files
-					m.cursor = 0
-					m.taskFilter = true
-					m.tagFilter = false // Clear tag filter when switching to task filter
-					m.textFilter = false // Clear text filter when switching to task filter
-					m.dailyFilter = false // Clear daily filter when switching to task filter
-					m.oldFilter = false // Clear old filter when switching to task filter
-				}
+				// Narrow the current results to notes with open tasks
+				m.pushFilter(noteFilter{Kind: filterTask})
 			}
 
 		case "i":
@@ -1949,42 +2236,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "y":
 			if m.deleteMode {
-				// Confirm deletion
+				// Confirm deletion, keeping the note in the trash
 				deletedFile := filepath.Base(m.deleteFile)
-				if err := os.Remove(m.deleteFile); err == nil {
-					// Successfully deleted, refresh file list
-					files, _ := findMarkdownFiles(m.cwd, m.config)
-					m.files = m.applySorting(files)
+				if trash, err := moveToTrash(m.cwd, m.deleteFile); err == nil {
+					op := newOperation("deletion of " + deletedFile)
+					op.trashed(m.deleteFile, trash)
+					
+					// Successfully deleted, drop it from the index
+					m.index.Remove(m.deleteFile)
+					m.refreshFiles()
 					
 					// If we had filters applied, reapply them
-					if m.taskFilter {
-						if taskFiles, err := searchTasks(m.cwd); err == nil {
-							m.filtered = m.applySorting(taskFiles)
-						} else {
-							m.filtered = m.files
-						}
-					} else if m.dailyFilter {
-						if dailyFiles, err := searchDailyNotes(m.cwd); err == nil {
-							m.filtered = m.applySorting(dailyFiles)
-						} else {
-							m.filtered = m.files
-						}
-					} else if m.search.Value() != "" {
-						m.filtered = m.applySorting(filterFiles(m.files, m.search.Value()))
-					} else {
-						m.filtered = m.files
-					}
+					m.applyActiveFilters()
 					
 					// Adjust cursor position
-					if m.cursor >= len(m.filtered) {
-						if len(m.filtered) > 0 {
-							m.cursor = len(m.filtered) - 1
-						} else {
-							m.cursor = 0
-						}
-					}
+					m.keepCursorOn("")
 					// Show success message
-					cmds = append(cmds, ui.ShowSuccess(fmt.Sprintf("Deleted %s", deletedFile)))
+					cmds = append(cmds, m.record(op, ui.ShowSuccess(fmt.Sprintf("Moved %s to the trash", deletedFile))))
 				} else {
 					// Show error message
 					cmds = append(cmds, ui.ShowError(fmt.Sprintf("Failed to delete %s", deletedFile)))
@@ -2047,9 +2315,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					})
 				} else {
 					// Use internal preview popover
-					m.previewFile = m.selected
+					m.openPreview(m.selected, 0, -1)
 					m.previewMode = true
-					m.previewScroll = 0
+					// Jump to the first content match when one is filtering the list
+					m.previewQuery = m.snippetQuery
 					return m, m.loadPreviewForPopover()
 				}
 			}
@@ -2093,6 +2362,54 @@ func (m model) openInEditor() *exec.Cmd {
 	return cmd
 }
 
+// editorLineArgs are how editors that can open a file at a line are told
+// to, keyed by command name. {file} and {line} are filled in.
+var editorLineArgs = map[string]string{
+	"vi":          "+{line} {file}",
+	"vim":         "+{line} {file}",
+	"nvim":        "+{line} {file}",
+	"gvim":        "+{line} {file}",
+	"emacs":       "+{line} {file}",
+	"emacsclient": "+{line} {file}",
+	"nano":        "+{line} {file}",
+	"micro":       "+{line} {file}",
+	"kak":         "+{line} {file}",
+	"hx":          "{file}:{line}",
+	"subl":        "{file}:{line}",
+	"code":        "--goto {file}:{line}",
+	"codium":      "--goto {file}:{line}",
+}
+
+// openInEditorAt opens the selected file in the editor at line, using
+// editor_line_args or the arguments known for the editor. Editors that
+// aren't known just open the file.
+func (m model) openInEditorAt(line int) *exec.Cmd {
+	cmd := m.openInEditor()
+	if line <= 0 || len(cmd.Args) < 2 {
+		return cmd
+	}
+	template := m.config.EditorLineArgs
+	if template == "" {
+		name := strings.TrimSuffix(filepath.Base(cmd.Args[0]), ".exe")
+		template = editorLineArgs[name]
+	}
+	if template == "" {
+		return cmd
+	}
+	if !strings.Contains(template, "{file}") {
+		template += " {file}"
+	}
+
+	file := cmd.Args[len(cmd.Args)-1]
+	args := cmd.Args[:len(cmd.Args)-1]
+	for _, arg := range strings.Fields(template) {
+		arg = strings.ReplaceAll(arg, "{line}", strconv.Itoa(line))
+		args = append(args, strings.ReplaceAll(arg, "{file}", file))
+	}
+	cmd.Args = args
+	return cmd
+}
+
 // Open file in external preview command
 func (m model) openInPreview() *exec.Cmd {
 	if m.config.PreviewCommand == "" {
@@ -2141,6 +2458,58 @@ func (m *model) syncUIState() {
 	m.ui.DeleteMode = m.deleteMode
 	m.ui.SortMode = m.sortMode
 	m.ui.OldMode = m.oldMode
+	m.ui.ViewMode = m.viewMode
+	m.ui.BacklinksMode = m.backlinksMode
+	m.ui.BacklinkTarget = m.backlinkTarget
+	m.ui.BacklinkCursor = m.backlinkCursor
+	m.ui.BacklinkSources, m.ui.BacklinkContexts = nil, nil
+	if m.backlinksMode {
+		sources, bySource := backlinkSources(m.index.Backlinks(m.backlinkTarget))
+		m.ui.BacklinkSources = sources
+		m.ui.BacklinkContexts = make(map[string][]string, len(sources))
+		for _, source := range sources {
+			for _, link := range bySource[source] {
+				m.ui.BacklinkContexts[source] = append(m.ui.BacklinkContexts[source], fmt.Sprintf("%d: %s", link.Line, link.Context))
+			}
+		}
+	}
+	m.ui.TaskMode = m.taskMode
+	m.ui.TaskTagMode = m.taskTagMode
+	m.ui.TaskTag = m.taskTag
+	m.ui.TaskShowDone = m.taskShowDone
+	m.ui.TaskCursor = m.taskCursor
+	m.ui.TaskAgenda = m.taskAgenda
+	m.ui.TaskSort = m.taskSort
+	m.ui.TaskItems, m.ui.TaskFiles, m.ui.TaskLines, m.ui.TaskMeta, m.ui.TaskHeaders = nil, nil, nil, nil, nil
+	today := time.Now().Format("2006-01-02")
+	for i, task := range m.tasks {
+		box := "[ ]"
+		if task.Done {
+			box = "[x]"
+		}
+		m.ui.TaskItems = append(m.ui.TaskItems, box+" "+task.Summary)
+		m.ui.TaskFiles = append(m.ui.TaskFiles, task.Path)
+		m.ui.TaskLines = append(m.ui.TaskLines, task.Line)
+		m.ui.TaskMeta = append(m.ui.TaskMeta, task.metadataLabel())
+		
+		// Head each agenda section
+		header := ""
+		if m.taskAgenda {
+			if group := task.group(today); i == 0 || m.tasks[i-1].group(today) != group {
+				header = group.String()
+			}
+		}
+		m.ui.TaskHeaders = append(m.ui.TaskHeaders, header)
+	}
+	m.ui.ViewCursor = m.viewCursor
+	m.ui.ActiveView = m.activeView
+	m.ui.Views, m.ui.ViewSummaries = nil, nil
+	if m.viewMode {
+		for _, view := range m.config.Views {
+			m.ui.Views = append(m.ui.Views, view.Name)
+			m.ui.ViewSummaries = append(m.ui.ViewSummaries, view.Summary())
+		}
+	}
 	m.ui.RenameMode = m.renameMode
 	
 	// Update inputs
@@ -2149,90 +2518,130 @@ func (m *model) syncUIState() {
 	m.ui.TagInput = m.tagInput
 	m.ui.TagCreateInput = m.tagCreateInput
 	m.ui.OldInput = m.oldInput
+	m.ui.TaskTagInput = m.taskTagInput
+	m.ui.MetaInput = m.metaInput
+	
+	m.ui.PeriodMode = m.periodMode
+	m.ui.Periods = nil
+	for _, p := range notePeriods(m.config) {
+		m.ui.Periods = append(m.ui.Periods, ui.PeriodKey{Name: p.Name, Key: p.Key})
+	}
+	
+	// Denote sync dialog
+	m.ui.SyncMode = m.syncMode
+	m.ui.SyncFile, m.ui.SyncDiff = "", nil
+	if m.syncDrift != nil {
+		m.ui.SyncFile = m.syncDrift.Path
+		m.ui.SyncDiff = m.syncDrift.Diff()
+	}
+	
+	// Trash browser
+	m.ui.TrashMode = m.trashMode
+	m.ui.TrashCursor = m.trashCursor
+	m.ui.TrashPurge = m.trashPurge
+	m.ui.TrashItems, m.ui.TrashDetails = nil, nil
+	for _, item := range m.trashItems {
+		m.ui.TrashItems = append(m.ui.TrashItems, getDisplayName(item.Original, m.cwd))
+		m.ui.TrashDetails = append(m.ui.TrashDetails, "deleted "+item.Deleted.Format("2006-01-02 15:04"))
+	}
+	
+	// Metadata editor
+	m.ui.MetaMode = m.metaMode
+	m.ui.MetaFile = m.metaFile
+	m.ui.MetaCursor = m.metaCursor
+	m.ui.MetaEdit = m.metaEdit
+	m.ui.MetaRows, m.ui.MetaHeaders, m.ui.MetaSuggestions = nil, nil, nil
+	if m.metaMode {
+		for i, field := range m.metaFields {
+			header := ""
+			if i == 0 {
+				header = "Frontmatter"
+			}
+			row := fmt.Sprintf("%-12s %s", field.Key+":", field.Value)
+			if field.Tag {
+				row = "#" + field.Value
+				if i == 0 || !m.metaFields[i-1].Tag {
+					header = "Tags"
+				}
+			} else if field.ReadOnly {
+				row += " (edit in the note)"
+			}
+			m.ui.MetaRows = append(m.ui.MetaRows, row)
+			m.ui.MetaHeaders = append(m.ui.MetaHeaders, header)
+		}
+		if m.metaEdit == "tag" {
+			m.ui.MetaSuggestions = m.metaTagSuggestions(m.metaInput.Value())
+		}
+	}
+	
+	// Calendar
+	m.ui.CalendarMode = m.calendarMode
+	m.ui.CalendarDay = m.calendarDay
+	m.ui.CalendarNotes = nil
+	if m.calendarMode {
+		m.ui.CalendarNotes = make(map[string]bool)
+		daily, _ := findPeriod(m.config, "daily")
+		for date := range periodicNoteDates(m.index, daily) {
+			m.ui.CalendarNotes[date] = true
+		}
+	}
 	
 	// Update other state
 	m.ui.PreviewContent = m.previewContent
 	m.ui.PreviewFile = m.previewFile
 	m.ui.PreviewScroll = m.previewScroll
+	m.ui.SplitPreview = m.splitContent
+	m.ui.SplitPreviewFile = m.splitShown
 	m.ui.DeleteFile = m.deleteFile
 	m.ui.RenameFile = m.renameFile
 	m.ui.PendingTitle = m.pendingTitle
+	m.ui.TemplateMode = m.templateMode
+	m.ui.TemplateCursor = m.templateCursor
+	m.ui.Templates = nil
+	if m.templateMode {
+		m.ui.Templates = append(m.ui.Templates, "Blank note")
+		for _, tmpl := range m.templates {
+			m.ui.Templates = append(m.ui.Templates, tmpl.Name)
+		}
+	}
 	m.ui.CurrentSort = m.currentSort
 	m.ui.ReversedSort = m.reversedSort
-	m.ui.OldDays = m.oldDays
-	m.ui.TextFilter = m.textFilter
-	m.ui.TagFilter = m.tagFilter
-	m.ui.TaskFilter = m.taskFilter
-	m.ui.DailyFilter = m.dailyFilter
-	m.ui.OldFilter = m.oldFilter
-}
-
-func (m model) renderPreviewPopover() string {
-	// Create a popover style
-	popoverStyle := lipgloss.NewStyle().
-		Width(m.width * 80 / 100).          // 80% of terminal width
-		Height(m.height * 80 / 100).        // 80% of terminal height
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
-		Padding(1, 2)
-	
-	// Position in center
-	centerStyle := lipgloss.NewStyle().
-		Width(m.width).
-		Height(m.height).
-		Align(lipgloss.Center, lipgloss.Center)
-	
-	// Header with filename
-	header := lipgloss.NewStyle().
-		Bold(true).
-		MarginBottom(1).
-		Render(getEnhancedDisplayName(m.previewFile, m.cwd, m.config.ShowTitles))
-	
-	// Footer with controls
-	footer := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		MarginTop(1).
-		Render("[Esc] close  [↑↓/jk] scroll  [e] edit")
-	
-	// Calculate available height for content
-	contentHeight := (m.height * 80 / 100) - 8 // Account for borders, padding, header, footer
-	
-	// Split content into lines and handle scrolling
-	lines := strings.Split(m.previewContent, "\n")
-	visibleLines := lines
-	
-	if len(lines) > contentHeight {
-		// Apply scrolling
-		end := m.previewScroll + contentHeight
-		if end > len(lines) {
-			end = len(lines)
-		}
-		visibleLines = lines[m.previewScroll:end]
-		
-		// Add scroll indicator
-		scrollInfo := fmt.Sprintf(" (line %d/%d)", m.previewScroll+1, len(lines))
-		header += lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(scrollInfo)
-	}
-	
-	// Join the visible content
-	content := strings.Join(visibleLines, "\n")
+	m.ui.Filters = m.filterLabels()
 	
-	// Combine everything
-	popoverContent := lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		content,
-		footer,
-	)
+	// Fuzzy match positions from the latest filename search
+	m.ui.Highlights = m.highlights
 	
-	popover := popoverStyle.Render(popoverContent)
-	return centerStyle.Render(popover)
+	// Content search snippets, shown only while that search is in effect
+	m.ui.ContentSearch = m.contentSearch
+	m.ui.SnippetQuery = m.snippetQuery
+	m.ui.Snippets = nil
+	if len(m.contentMatches) > 0 {
+		m.ui.Snippets = make(map[string][]string, len(m.contentMatches))
+		for path, matches := range m.contentMatches {
+			for _, match := range matches {
+				m.ui.Snippets[path] = append(m.ui.Snippets[path], fmt.Sprintf("%d: %s", match.Line, match.Text))
+			}
+		}
+	}
 }
 
 func main() {
+	// Subcommands take over before the TUI's flags are parsed
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "sync":
+			os.Exit(runSyncCommand(os.Args[2:], os.Stdin, os.Stdout))
+		case "migrate":
+			os.Exit(runMigrateCommand(os.Args[2:], os.Stdout))
+		}
+	}
+	
 	// Parse command line flags
 	var tag = flag.String("tag", "", "Filter notes by tag (e.g., --tag=@mikeh)")
+	var view = flag.String("view", "", "Start with a saved view from config.toml (e.g., --view=Inbox)")
+	var query = flag.String("query", "", "Filter notes with a search query (e.g., --query='tag:work has:tasks')")
 	var openID = flag.String("open-id", "", "Open note with specific Denote identifier (e.g., --open-id=20241225T093015)")
+	var daily = flag.String("daily", "", "Open or create the daily note for a day (e.g., --daily=2025-06-23 or --daily=yesterday)")
 	flag.Parse()
 
 	// Load config first
@@ -2280,14 +2689,10 @@ func main() {
 		// Look for file with matching Denote identifier
 		var matchedFile string
 		for _, file := range files {
-			filename := filepath.Base(file)
-			// Check if filename starts with the exact identifier followed by - or _
-			if len(filename) > 16 && strings.HasPrefix(filename, *openID) {
-				// Make sure the identifier is followed by - or _ (not another digit)
-				if filename[15] == '-' || filename[15] == '_' {
-					matchedFile = file
-					break
-				}
+			// The identifier may sit anywhere in the name, marked with @@
+			if name, ok := denote.Parse(file); ok && name.Identifier == *openID {
+				matchedFile = file
+				break
 			}
 		}
 		
@@ -2342,11 +2747,16 @@ func main() {
 		os.Exit(0)
 	}
 
-	p := tea.NewProgram(initialModel(*tag), tea.WithAltScreen())
+	p := tea.NewProgram(initialModel(*tag, *query, *view, *daily), tea.WithAltScreen())
 	m, err := p.Run()
 	if err != nil {
 		log.Fatal(err)
 	}
+	
+	// Stop watching before handing the terminal to an editor
+	if m, ok := m.(model); ok {
+		m.watcher.Close()
+	}
 
 	// If a file was selected, open it in editor
 	if m, ok := m.(model); ok && m.selected != "" {
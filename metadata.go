@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pdxmph/notes-tui/internal/frontmatter"
+)
+
+// metaField is one row of the metadata editor: a frontmatter key, or one
+// of the note's frontmatter tags
+type metaField struct {
+	Key      string
+	Value    string
+	Tag      bool // one entry of the tags list
+	ReadOnly bool // lists and tables other than tags are shown, not edited
+}
+
+// metaCoreKeys are offered for every note, even when it lacks them
+var metaCoreKeys = []string{"title", "date", "identifier"}
+
+// metaFieldsFrom lays out a note's frontmatter as editor rows: the core
+// keys, any other keys in file order, then one row per tag
+func metaFieldsFrom(meta *frontmatter.Frontmatter) []metaField {
+	var fields []metaField
+	for _, key := range metaCoreKeys {
+		field := metaField{Key: key}
+		if meta != nil {
+			field.Value = meta.Get(key)
+			field.ReadOnly = meta.Has(key) && !meta.Scalar(key)
+		}
+		fields = append(fields, field)
+	}
+	if meta == nil {
+		return fields
+	}
+
+	for _, key := range meta.Keys() {
+		if key == "tags" || slices.Contains(metaCoreKeys, key) {
+			continue
+		}
+		fields = append(fields, metaField{Key: key, Value: meta.Get(key), ReadOnly: !meta.Scalar(key)})
+	}
+	for _, tag := range meta.Strings("tags") {
+		fields = append(fields, metaField{Key: "tags", Value: tag, Tag: true})
+	}
+	return fields
+}
+
+// openMetadataEditor loads a note's frontmatter into the editor
+func (m *model) openMetadataEditor(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	doc, err := frontmatter.Parse(string(content))
+	if err != nil {
+		return err
+	}
+
+	m.metaMode = true
+	m.metaFile = path
+	m.metaFields = metaFieldsFrom(doc.Meta)
+	m.metaCursor = 0
+	m.metaEdit = ""
+	return nil
+}
+
+// firstTagRow returns the index of the first tag row, or len(fields)
+func firstTagRow(fields []metaField) int {
+	for i, field := range fields {
+		if field.Tag {
+			return i
+		}
+	}
+	return len(fields)
+}
+
+// startMetaInput opens the editor's input for a value, a new tag or a new
+// key, prefilled with value
+func (m *model) startMetaInput(edit, value string) {
+	m.metaEdit = edit
+	m.metaCompletions = nil
+	m.metaInput.SetValue(value)
+	m.metaInput.CursorEnd()
+	m.metaInput.Focus()
+}
+
+// closeMetaInput hides the editor's input
+func (m *model) closeMetaInput() {
+	m.metaEdit = ""
+	m.metaCompletions = nil
+	m.metaInput.SetValue("")
+	m.metaInput.Blur()
+}
+
+// commitMetaInput applies what was typed to the rows being edited
+func (m *model) commitMetaInput() {
+	value := strings.TrimSpace(m.metaInput.Value())
+	edit := m.metaEdit
+	m.closeMetaInput()
+
+	switch edit {
+	case "value":
+		if m.metaCursor < len(m.metaFields) {
+			m.metaFields[m.metaCursor].Value = value
+		}
+
+	case "tag":
+		// Several tags may be added at once, separated by commas
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+			if tag == "" || m.metaHasTag(tag) {
+				continue
+			}
+			m.metaFields = append(m.metaFields, metaField{Key: "tags", Value: tag, Tag: true})
+			m.metaCursor = len(m.metaFields) - 1
+		}
+
+	case "key":
+		if value == "" {
+			return
+		}
+		if value == "tags" {
+			m.startMetaInput("tag", "")
+			return
+		}
+		for i, field := range m.metaFields {
+			if !field.Tag && field.Key == value {
+				m.metaCursor = i
+				if !field.ReadOnly {
+					m.startMetaInput("value", field.Value)
+				}
+				return
+			}
+		}
+		// New keys go after the existing ones, above the tags
+		at := firstTagRow(m.metaFields)
+		m.metaFields = slices.Insert(m.metaFields, at, metaField{Key: value})
+		m.metaCursor = at
+		m.startMetaInput("value", "")
+	}
+}
+
+// removeMetaField drops the row under the cursor. Core keys are emptied
+// instead, which removes them from the frontmatter on save.
+func (m *model) removeMetaField() {
+	if m.metaCursor >= len(m.metaFields) {
+		return
+	}
+	field := m.metaFields[m.metaCursor]
+	if !field.Tag && slices.Contains(metaCoreKeys, field.Key) {
+		m.metaFields[m.metaCursor].Value = ""
+		m.metaFields[m.metaCursor].ReadOnly = false
+		return
+	}
+	m.metaFields = slices.Delete(m.metaFields, m.metaCursor, m.metaCursor+1)
+	if m.metaCursor >= len(m.metaFields) {
+		m.metaCursor = len(m.metaFields) - 1
+	}
+}
+
+// metaHasTag reports whether the rows already hold tag (case-insensitive)
+func (m *model) metaHasTag(tag string) bool {
+	want := normalizeTag(tag)
+	for _, field := range m.metaFields {
+		if field.Tag && normalizeTag(field.Value) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// metaTagSuggestions lists known tags starting with what has been typed
+// that the note doesn't carry yet
+func (m *model) metaTagSuggestions(prefix string) []string {
+	prefix = normalizeTag(prefix)
+	var suggestions []string
+	for _, tag := range allTags(m.index) {
+		if strings.HasPrefix(normalizeTag(tag), prefix) && !m.metaHasTag(tag) {
+			suggestions = append(suggestions, tag)
+		}
+	}
+	return suggestions
+}
+
+// completeMetaTag fills the tag input with the next known tag matching
+// what was typed before the first Tab
+func (m *model) completeMetaTag() {
+	if m.metaCompletions == nil {
+		m.metaCompletions = m.metaTagSuggestions(m.metaInput.Value())
+		m.metaCompletion = 0
+	}
+	if len(m.metaCompletions) == 0 {
+		return
+	}
+	m.metaInput.SetValue(m.metaCompletions[m.metaCompletion%len(m.metaCompletions)])
+	m.metaInput.CursorEnd()
+	m.metaCompletion++
+}
+
+// saveMetadata writes edited rows back into a note's frontmatter. Only
+// changed keys are rewritten; the body and everything else stay as they
+// were. A note without frontmatter gets a YAML block if anything was set.
+func saveMetadata(idx *NoteIndex, path string, fields []metaField) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	doc, err := frontmatter.Parse(string(content))
+	if err != nil {
+		return err
+	}
+	meta := doc.Meta
+	if meta == nil {
+		meta = frontmatter.New(frontmatter.YAML)
+	}
+
+	var tags []string
+	kept := map[string]bool{"tags": true}
+	for _, field := range fields {
+		if field.Tag {
+			tags = append(tags, field.Value)
+			continue
+		}
+		kept[field.Key] = true
+		if field.ReadOnly || meta.Get(field.Key) == field.Value {
+			continue
+		}
+
+		switch {
+		case field.Value == "":
+			meta.Delete(field.Key)
+		case field.Key == "title":
+			// Titles are text even when they look like a number or date
+			err = meta.Set(field.Key, field.Value)
+		default:
+			err = meta.SetText(field.Key, field.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Keys removed in the editor
+	for _, key := range meta.Keys() {
+		if !kept[key] {
+			meta.Delete(key)
+		}
+	}
+	if !slices.Equal(tags, meta.Strings("tags")) {
+		if len(tags) == 0 {
+			meta.Delete("tags")
+		} else if err := meta.Set("tags", tags); err != nil {
+			return err
+		}
+	}
+
+	if doc.Meta == nil {
+		if len(meta.Keys()) == 0 {
+			return nil
+		}
+		doc.Meta = meta
+		if !strings.HasPrefix(doc.Body, "\n") {
+			doc.Body = "\n" + doc.Body
+		}
+	}
+	updated, err := doc.String()
+	if err != nil {
+		return err
+	}
+	if updated == string(content) {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(updated), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return idx.Update(path)
+}
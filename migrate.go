@@ -0,0 +1,333 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdxmph/notes-tui/internal/denote"
+)
+
+// migration is one note renamed by `notes-tui migrate --denote`
+type migration struct {
+	OldPath string `json:"old_path"` // relative to the notes directory
+	NewPath string `json:"new_path"`
+	Links   int    `json:"links_rewritten"` // links in other notes pointing here
+	Status  string `json:"status"`          // planned, renamed or an error
+}
+
+// planDenoteMigration works out a Denote name for every indexed note that
+// doesn't have one. Identifiers already in use, by Denote notes or earlier
+// in the plan, are moved on a second at a time until they are free.
+func planDenoteMigration(idx *NoteIndex, config Config) (map[string]string, []string, error) {
+	taken := make(map[string]bool)
+	var legacy []string
+	for _, path := range idx.Paths() {
+		if name, ok := denote.Parse(filepath.Base(path)); ok {
+			taken[name.Identifier] = true
+		} else {
+			legacy = append(legacy, path)
+		}
+	}
+
+	renames := make(map[string]string)
+	targets := make(map[string]bool)
+	for _, path := range legacy {
+		name, err := denoteNameFor(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for {
+			newPath := filepath.Join(filepath.Dir(path), name.Format(config.denoteOrder()))
+			_, statErr := os.Stat(newPath)
+			if !taken[name.Identifier] && !targets[newPath] && os.IsNotExist(statErr) {
+				taken[name.Identifier] = true
+				targets[newPath] = true
+				renames[path] = newPath
+				break
+			}
+			t, ok := name.Time()
+			if !ok {
+				return nil, nil, fmt.Errorf("%s: invalid identifier %s", path, name.Identifier)
+			}
+			name.Identifier = denote.IdentifierFor(t.Add(time.Second))
+		}
+	}
+	return renames, legacy, nil
+}
+
+// rewriteMigratedLinks rewrites markdown links, and wiki links that name a
+// file, in source that point at renamed notes. Links are resolved against
+// the index, which still has the old paths, while the content is read from
+// current, where source is now. It returns the new content and how many
+// links changed for each target, which is empty when nothing needs to
+// change.
+func rewriteMigratedLinks(idx *NoteIndex, source, current string, renames map[string]string) (string, map[string]int, error) {
+	entry, ok := idx.Get(source)
+	if !ok {
+		return "", nil, nil
+	}
+
+	// Only lines holding a link to a renamed note are touched, which also
+	// keeps code blocks and frontmatter as they are
+	lines := make(map[int]bool)
+	for _, link := range entry.Links {
+		if target, ok := idx.ResolveLink(source, link); ok && renames[target] != "" && link.Kind != linkDenote {
+			lines[link.Line] = true
+		}
+	}
+	if len(lines) == 0 {
+		return "", nil, nil
+	}
+
+	data, err := os.ReadFile(current)
+	if err != nil {
+		return "", nil, err
+	}
+	counts := make(map[string]int)
+	content := strings.Split(string(data), "\n")
+	for num := range lines {
+		if num-1 >= len(content) {
+			continue
+		}
+		line := content[num-1]
+		line = markdownLinkPattern.ReplaceAllStringFunc(line, func(match string) string {
+			dest := markdownLinkPattern.FindStringSubmatch(match)[1]
+			target, ok := relativeNotePath(dest)
+			if !ok {
+				return match
+			}
+			oldPath := filepath.Clean(filepath.Join(filepath.Dir(source), filepath.FromSlash(target)))
+			newPath, renamed := renames[oldPath]
+			if !renamed {
+				return match
+			}
+			counts[oldPath]++
+			return strings.Replace(match, "("+dest, "("+renameLinkDest(dest, filepath.Base(newPath)), 1)
+		})
+		line = wikiLinkPattern.ReplaceAllStringFunc(line, func(match string) string {
+			inner := match[2 : len(match)-2]
+			target, label, hasLabel := strings.Cut(inner, "|")
+			link := noteLink{Kind: linkWiki, Target: strings.TrimSpace(target)}
+			oldPath, ok := idx.ResolveLink(source, link)
+			if !ok || renames[oldPath] == "" {
+				return match
+			}
+			// Links by title keep working; only links naming the file change
+			stem := strings.TrimSuffix(filepath.Base(oldPath), filepath.Ext(oldPath))
+			if !strings.EqualFold(link.Target, stem) {
+				return match
+			}
+			counts[oldPath]++
+			newName := filepath.Base(renames[oldPath])
+			inner = strings.TrimSuffix(newName, filepath.Ext(newName))
+			if hasLabel {
+				inner += "|" + label
+			}
+			return "[[" + inner + "]]"
+		})
+		content[num-1] = line
+	}
+	return strings.Join(content, "\n"), counts, nil
+}
+
+// renameLinkDest swaps the file name in a link destination for a new one,
+// keeping its directory, any #anchor and any <> around it
+func renameLinkDest(dest, newName string) string {
+	inner := dest
+	wrapped := strings.HasPrefix(inner, "<") && strings.HasSuffix(inner, ">")
+	if wrapped {
+		inner = inner[1 : len(inner)-1]
+	}
+	file, anchor, hasAnchor := strings.Cut(inner, "#")
+	if slash := strings.LastIndex(file, "/"); slash >= 0 {
+		file = file[:slash+1] + newName
+	} else {
+		file = newName
+	}
+	if hasAnchor {
+		file += "#" + anchor
+	}
+	if wrapped {
+		return "<" + file + ">"
+	}
+	return file
+}
+
+// writeMigrationReport writes the renames as CSV, or JSON when the report
+// file ends in .json
+func writeMigrationReport(path string, migrations []migration) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(migrations)
+	}
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"old_path", "new_path", "links_rewritten", "status"})
+	for _, m := range migrations {
+		w.Write([]string{m.OldPath, m.NewPath, strconv.Itoa(m.Links), m.Status})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// runMigrateCommand implements `notes-tui migrate --denote`: it gives every
+// note without a Denote name one, following the same rules as R, fixes
+// links to the renamed notes and writes a report of what moved
+func runMigrateCommand(args []string, stdout io.Writer) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	toDenote := flags.Bool("denote", false, "Rename notes to Denote filenames")
+	dryRun := flags.Bool("dry-run", false, "Show and report the renames without changing anything")
+	reportPath := flags.String("report", "denote-migration.csv", "Where to write the report of old and new paths (.csv or .json)")
+	flags.Usage = func() {
+		fmt.Fprintln(stdout, "Usage: notes-tui migrate --denote [--dry-run] [--report=FILE] [directory]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if !*toDenote {
+		flags.Usage()
+		return 2
+	}
+
+	config := LoadConfig()
+	root := config.NotesDirectory
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	if root == "" {
+		root = "."
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+
+	idx := NewNoteIndex(root, config)
+	if err := idx.Build(); err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+
+	renames, order, err := planDenoteMigration(idx, config)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+	if len(renames) == 0 {
+		fmt.Fprintln(stdout, "Every note already has a Denote name")
+		return 0
+	}
+
+	// The whole migration is one operation, so u in the app undoes it
+	op := newOperation(fmt.Sprintf("migration of %d notes", len(renames)))
+
+	// Rename first, so links are only rewritten to notes that really moved
+	failed := 0
+	migrations := make([]migration, len(order))
+	renamed := make(map[string]string)
+	for i, path := range order {
+		oldRel, _ := filepath.Rel(root, path)
+		newRel, _ := filepath.Rel(root, renames[path])
+		migrations[i] = migration{OldPath: filepath.ToSlash(oldRel), NewPath: filepath.ToSlash(newRel), Status: "planned"}
+		if *dryRun {
+			renamed[path] = renames[path]
+			continue
+		}
+		if err := os.Rename(path, renames[path]); err != nil {
+			migrations[i].Status = err.Error()
+			failed++
+			continue
+		}
+		migrations[i].Status = "renamed"
+		renamed[path] = renames[path]
+		op.renamed(path, renames[path])
+	}
+
+	// The index still has every note at its old path, which is what the
+	// links resolve against; sources that moved are edited at their new one
+	links := make(map[string]int)
+	rewritten := 0
+	for _, source := range idx.Paths() {
+		current := source
+		if !*dryRun && renamed[source] != "" {
+			current = renamed[source]
+		}
+		updated, counts, err := rewriteMigratedLinks(idx, source, current, renamed)
+		if err != nil {
+			rel, _ := filepath.Rel(root, current)
+			fmt.Fprintf(stdout, "%s: %v\n", rel, err)
+			failed++
+			continue
+		}
+		if len(counts) == 0 {
+			continue
+		}
+		for target, n := range counts {
+			links[target] += n
+		}
+		rewritten++
+		if *dryRun {
+			continue
+		}
+		info, err := os.Stat(current)
+		if err == nil {
+			err = op.edit(current)
+		}
+		if err == nil {
+			err = os.WriteFile(current, []byte(updated), info.Mode().Perm())
+		}
+		if err != nil {
+			rel, _ := filepath.Rel(root, current)
+			fmt.Fprintf(stdout, "%s: %v\n", rel, err)
+			failed++
+		}
+	}
+
+	for i, path := range order {
+		migrations[i].Links = links[path]
+		fmt.Fprintf(stdout, "%s -> %s\n", migrations[i].OldPath, migrations[i].NewPath)
+	}
+
+	if !*dryRun {
+		journal := openJournal(root)
+		if err := journal.commit(op); err != nil {
+			fmt.Fprintf(stdout, "undo history: %v\n", err)
+			failed++
+		}
+	}
+
+	if err := writeMigrationReport(*reportPath, migrations); err != nil {
+		fmt.Fprintf(stdout, "report: %v\n", err)
+		return 1
+	}
+
+	if *dryRun {
+		fmt.Fprintf(stdout, "\nWould rename %d notes and fix links in %d\n", len(migrations), rewritten)
+	} else {
+		fmt.Fprintf(stdout, "\nRenamed %d notes and fixed links in %d\n", len(migrations), rewritten)
+	}
+	fmt.Fprintf(stdout, "Report written to %s\n", *reportPath)
+	if failed > 0 {
+		fmt.Fprintf(stdout, "%d errors\n", failed)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/notes-tui/internal/denote"
+	"github.com/pdxmph/notes-tui/internal/ui"
+)
+
+// PeriodicConfig overrides the defaults for one kind of periodic note:
+//
+//	[periodic.weekly]
+//	filename = "reviews/{year}-W{week}.md"
+//	template = "weekly"
+//	key = "W"
+//	tag = "review"
+type PeriodicConfig struct {
+	Filename string `toml:"filename"` // traditional filename, with {year} {month} {day} {week} {quarter}
+	Template string `toml:"template"` // template name
+	Key      string `toml:"key"`      // key that opens or creates the current note
+	Tag      string `toml:"tag"`      // Denote keyword marking the note
+}
+
+// notePeriod is one kind of periodic note: daily, weekly, monthly or
+// quarterly
+type notePeriod struct {
+	Name     string
+	Filename string
+	Template string
+	Key      string
+	Tag      string
+
+	pattern *regexp.Regexp // Filename as a regexp, see filenamePattern
+}
+
+// defaultPeriods are the periodic notes there are, in order
+var defaultPeriods = []notePeriod{
+	{Name: "daily", Filename: "{year}-{month}-{day}-daily.md", Key: "d", Tag: "daily"},
+	{Name: "weekly", Filename: "{year}-W{week}-weekly.md", Key: "W", Tag: "weekly"},
+	{Name: "monthly", Filename: "{year}-{month}-monthly.md", Key: "M", Tag: "monthly"},
+	{Name: "quarterly", Filename: "{year}-Q{quarter}-quarterly.md", Key: "Q", Tag: "quarterly"},
+}
+
+// periodTokenPattern matches a {token} in a filename pattern
+var periodTokenPattern = regexp.MustCompile(`\\\{(year|month|day|week|quarter)\\\}`)
+
+// notePeriods returns every kind of periodic note with the config's
+// overrides applied
+func notePeriods(config Config) []notePeriod {
+	periods := make([]notePeriod, len(defaultPeriods))
+	copy(periods, defaultPeriods)
+	for i := range periods {
+		p := &periods[i]
+		if p.Name == "daily" {
+			p.Template = config.DailyTemplate
+		}
+		override, ok := config.Periodic[p.Name]
+		if !ok {
+			continue
+		}
+		if override.Filename != "" {
+			p.Filename = override.Filename
+		}
+		if override.Template != "" {
+			p.Template = override.Template
+		}
+		if override.Key != "" && periodicKeyError(config, p.Name, override.Key) == nil {
+			p.Key = override.Key
+		}
+		if override.Tag != "" {
+			p.Tag = strings.TrimPrefix(override.Tag, "#")
+		}
+	}
+	for i := range periods {
+		periods[i].pattern = periods[i].filenamePattern()
+	}
+	return periods
+}
+
+// builtinKeys are the keys the file list's normal-mode switch handles,
+// including those only used by its sort and delete prompts, plus the task
+// list's. Periodic note keys are checked before them, so they mustn't take
+// any of these over. TestBuiltinKeysCoverNormalMode keeps the list complete.
+var builtinKeys = []string{
+	"q", "ctrl+c", "e", "ctrl+e", "esc", "enter", "backspace",
+	"up", "down", "k", "j", "g", "G",
+	"/", "#", "n", "E", "X", "u", "U", "D", "P", "C",
+	"o", "O", "R", "S", "v", "b", "T",
+	"d", "m", "t", "i", "r", "y", "s", " ",
+}
+
+// periodicKeyError says why a key configured for a kind of periodic note
+// can't be used: it is already bound, or another kind of note has it.
+// Such keys are ignored and the default kept. A period's own default key
+// is always allowed.
+func periodicKeyError(config Config, name, key string) error {
+	for _, p := range defaultPeriods {
+		if p.Name == name && p.Key == key {
+			return nil
+		}
+	}
+	if slices.Contains(builtinKeys, key) {
+		return fmt.Errorf("periodic.%s: key %q is already bound", name, key)
+	}
+	for _, p := range defaultPeriods {
+		if p.Name == name {
+			continue
+		}
+		other := p.Key
+		if override := config.Periodic[p.Name].Key; override != "" {
+			other = override
+		}
+		if other == key {
+			return fmt.Errorf("periodic.%s: key %q is also used by %s notes", name, key, p.Name)
+		}
+	}
+	return nil
+}
+
+// findPeriod looks up a kind of periodic note by name
+func findPeriod(config Config, name string) (notePeriod, bool) {
+	for _, p := range notePeriods(config) {
+		if p.Name == strings.ToLower(name) {
+			return p, true
+		}
+	}
+	return notePeriod{}, false
+}
+
+// start returns the first day of the period containing t. Weeks are ISO
+// weeks, starting on Monday.
+func (p notePeriod) start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch p.Name {
+	case "weekly":
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "monthly":
+		return day.AddDate(0, 0, 1-day.Day())
+	case "quarterly":
+		month := time.Month((int(day.Month())-1)/3*3 + 1)
+		return time.Date(day.Year(), month, 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+// title names the note for the period containing t
+func (p notePeriod) title(t time.Time) string {
+	switch p.Name {
+	case "weekly":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("Weekly Note - %d-W%02d", year, week)
+	case "monthly":
+		return "Monthly Note - " + t.Format("January 2006")
+	case "quarterly":
+		return fmt.Sprintf("Quarterly Note - %d Q%d", t.Year(), (int(t.Month())+2)/3)
+	}
+	return "Daily Note - " + t.Format("Monday, January 2, 2006")
+}
+
+// body is the built-in content for a new note without a template
+func (p notePeriod) body(carried string) string {
+	switch p.Name {
+	case "weekly":
+		return "## Review\n\n## Next Week\n\n"
+	case "monthly":
+		return "## Review\n\n## Next Month\n\n"
+	case "quarterly":
+		return "## Review\n\n## Next Quarter\n\n"
+	}
+	return "## Tasks\n\n" + carried + "## Notes\n\n"
+}
+
+// expand fills in the filename pattern for the period containing t. In
+// weekly notes {year} is the ISO week's year.
+func (p notePeriod) expand(t time.Time) string {
+	start := p.start(t)
+	year, week := start.ISOWeek()
+	if p.Name != "weekly" {
+		year = start.Year()
+	}
+	return strings.NewReplacer(
+		"{year}", fmt.Sprintf("%04d", year),
+		"{month}", fmt.Sprintf("%02d", int(start.Month())),
+		"{day}", fmt.Sprintf("%02d", start.Day()),
+		"{week}", fmt.Sprintf("%02d", week),
+		"{quarter}", strconv.Itoa((int(start.Month())+2)/3),
+	).Replace(p.Filename)
+}
+
+// filenamePattern turns the filename pattern into a regexp capturing each
+// token. It matches the end of a path, so notes kept in any folder are
+// found, and a pattern with folders of its own matches those too.
+func (p notePeriod) filenamePattern() *regexp.Regexp {
+	digits := map[string]string{"year": `\d{4}`, "month": `\d{2}`, "day": `\d{2}`, "week": `\d{2}`, "quarter": `[1-4]`}
+	pattern := periodTokenPattern.ReplaceAllStringFunc(regexp.QuoteMeta(p.Filename), func(token string) string {
+		name := periodTokenPattern.FindStringSubmatch(token)[1]
+		return fmt.Sprintf("(?P<%s>%s)", name, digits[name])
+	})
+	return regexp.MustCompile("(?:^|/)" + pattern + "$")
+}
+
+// parseFilename reads the period's first day from a path (relative to the
+// notes directory) whose name follows the filename pattern
+func (p notePeriod) parseFilename(rel string) (time.Time, bool) {
+	pattern := p.pattern
+	if pattern == nil {
+		pattern = p.filenamePattern()
+	}
+	match := pattern.FindStringSubmatch(filepath.ToSlash(rel))
+	if match == nil {
+		return time.Time{}, false
+	}
+	values := map[string]int{"month": 1, "day": 1}
+	for i, name := range pattern.SubexpNames() {
+		if name != "" {
+			values[name], _ = strconv.Atoi(match[i])
+		}
+	}
+
+	var day time.Time
+	switch {
+	case values["week"] > 0:
+		// ISO week 1 is the one holding January 4th
+		jan4 := time.Date(values["year"], time.January, 4, 0, 0, 0, 0, time.Local)
+		day = p.start(jan4).AddDate(0, 0, 7*(values["week"]-1))
+	case values["quarter"] > 0:
+		day = time.Date(values["year"], time.Month(values["quarter"]*3-2), 1, 0, 0, 0, 0, time.Local)
+	default:
+		day = time.Date(values["year"], time.Month(values["month"]), values["day"], 0, 0, 0, 0, time.Local)
+		if day.Month() != time.Month(values["month"]) {
+			return time.Time{}, false
+		}
+	}
+	return p.start(day), true
+}
+
+// noteStart returns the first day (YYYY-MM-DD) of the period a note is
+// for, if it is a note of this kind: either following the filename pattern
+// or a Denote note carrying the period's tag
+func (p notePeriod) noteStart(idx *NoteIndex, entry *NoteEntry) (string, bool) {
+	rel, err := filepath.Rel(idx.root, entry.Path)
+	if err != nil {
+		rel = filepath.Base(entry.Path)
+	}
+	if day, ok := p.parseFilename(rel); ok {
+		return day.Format("2006-01-02"), true
+	}
+
+	name, ok := denote.Parse(filepath.Base(entry.Path))
+	if !ok {
+		return "", false
+	}
+	if !slices.Contains(name.Keywords, p.Tag) && name.Title != p.Name {
+		return "", false
+	}
+	created, err := time.ParseInLocation("2006-01-02", noteCreatedDate(entry), time.Local)
+	if err != nil {
+		return "", false
+	}
+	return p.start(created).Format("2006-01-02"), true
+}
+
+// periodicNoteDates maps the first day (YYYY-MM-DD) of each period that
+// has a note of this kind to that note
+func periodicNoteDates(idx *NoteIndex, p notePeriod) map[string]string {
+	dates := make(map[string]string)
+	for _, path := range idx.Paths() {
+		entry, ok := idx.Get(path)
+		if !ok {
+			continue
+		}
+		if start, ok := p.noteStart(idx, entry); ok {
+			if _, seen := dates[start]; !seen {
+				dates[start] = path
+			}
+		}
+	}
+	return dates
+}
+
+// searchPeriodicNotes keeps the files that are notes of this kind
+func searchPeriodicNotes(idx *NoteIndex, files []string, p notePeriod) []string {
+	return idx.Select(files, func(entry *NoteEntry) bool {
+		_, ok := p.noteStart(idx, entry)
+		return ok
+	})
+}
+
+// findPeriodicNote finds the note for the period containing t, in either
+// filename format
+func findPeriodicNote(idx *NoteIndex, p notePeriod, t time.Time) (string, error) {
+	path := filepath.Join(idx.root, filepath.FromSlash(p.expand(t)))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if path, ok := periodicNoteDates(idx, p)[p.start(t).Format("2006-01-02")]; ok {
+		return path, nil
+	}
+	return "", os.ErrNotExist
+}
+
+// periodicNoteFilename names a new note for the period containing t,
+// relative to the notes directory
+func periodicNoteFilename(config Config, p notePeriod, t time.Time) (string, string) {
+	if config.DenoteFilenames {
+		// The identifier takes the day's date and the current time
+		now := time.Now()
+		stamp := time.Date(t.Year(), t.Month(), t.Day(), now.Hour(), now.Minute(), now.Second(), 0, now.Location())
+		return generateDenoteName(p.Name, []string{p.Tag}, stamp, config)
+	}
+	return filepath.FromSlash(p.expand(t)), ""
+}
+
+// periodForKey returns the kind of periodic note a key opens
+func (m *model) periodForKey(key string) (notePeriod, bool) {
+	for _, p := range notePeriods(m.config) {
+		if p.Key == key {
+			return p, true
+		}
+	}
+	return notePeriod{}, false
+}
+
+// openPeriodicNote opens the note for the period containing t in the
+// editor, creating it first if there isn't one
+func (m *model) openPeriodicNote(p notePeriod, t time.Time) tea.Cmd {
+	// First, check if the note already exists
+	if existing, err := findPeriodicNote(m.index, p, t); err == nil {
+		m.selected = existing
+		// Find and select the file in the list
+		for i, f := range m.filtered {
+			if f == existing {
+				m.cursor = i
+				break
+			}
+		}
+		return tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
+			return clearSelectedMsg{}
+		})
+	}
+
+	// No note exists, create one in the current format
+	filename, identifier := periodicNoteFilename(m.config, p, t)
+	fullPath := filepath.Join(m.cwd, filename)
+	title := p.title(t)
+
+	// Denote notes carry the period's tag
+	var tags []string
+	if m.config.DenoteFilenames {
+		tags = []string{p.Tag}
+	}
+
+	// Bring unfinished tasks over from the previous daily note, unless
+	// filling in a day that has already passed
+	date := t.Format("2006-01-02")
+	carried := ""
+	carry, hasCarry := dailyCarryover{}, false
+	if p.Name == "daily" && (m.config.DailyCarryover == carryoverCopy || m.config.DailyCarryover == carryoverMove) &&
+		date >= time.Now().Format("2006-01-02") {
+		carry, hasCarry = collectCarryover(m.index, p, date)
+		if hasCarry {
+			carried = carry.section(m.index, fullPath)
+		}
+	}
+
+	// Use the period's template, or the built-in sections
+	var content string
+	if p.Template != "" {
+		tmpl, ok := findTemplate(m.config, p.Template)
+		if !ok {
+			return ui.ShowError(fmt.Sprintf("Template for %s notes not found: %s", p.Name, p.Template))
+		}
+		data := newTemplateData(title, identifier, tags, t)
+		data.Carryover = carried
+		rendered, err := tmpl.render(data, m.config)
+		if err != nil {
+			return ui.ShowError(err.Error())
+		}
+		content = rendered
+	} else {
+		content = generateNoteContent(title, m.config, identifier, tags, t) + p.body(carried)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return ui.ShowError(fmt.Sprintf("Error creating %s note: %v", p.Name, err))
+	}
+	if err := writeNewFile(fullPath, []byte(content)); err != nil {
+		return ui.ShowError(fmt.Sprintf("Error creating %s note: %v", p.Name, err))
+	}
+	op := newOperation("creation of " + filepath.Base(fullPath))
+	op.created(fullPath)
+	m.selected = fullPath
+	// Add the new file to the index and refresh the list
+	m.index.Update(fullPath)
+
+	// Only take the tasks out of the old note once the new one holds them
+	var status tea.Cmd
+	if hasCarry && m.config.DailyCarryover == carryoverMove {
+		// Leave the tasks where they are if the change couldn't be undone
+		err := op.edit(carry.Source)
+		if err == nil {
+			err = removeCarriedTasks(m.index, carry)
+		}
+		if err != nil {
+			status = ui.ShowError(fmt.Sprintf("Tasks copied but not removed from %s: %v", filepath.Base(carry.Source), err))
+		}
+	}
+	status = m.record(op, status)
+
+	m.refreshFiles()
+	m.clearFilters()
+	// Find and select the new file
+	for i, f := range m.filtered {
+		if f == fullPath {
+			m.cursor = i
+			break
+		}
+	}
+	return tea.Batch(status, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
+		return clearSelectedMsg{}
+	}))
+}
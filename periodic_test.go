@@ -0,0 +1,92 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// TestBuiltinKeysCoverNormalMode checks that every key the normal-mode
+// switch in main.go handles is in builtinKeys, so a periodic note key
+// can't silently take one over
+func TestBuiltinKeysCoverNormalMode(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "main.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var normal *ast.SwitchStmt
+	ast.Inspect(file, func(n ast.Node) bool {
+		sw, ok := n.(*ast.SwitchStmt)
+		if !ok || normal != nil || !switchesOnKey(sw) {
+			return true
+		}
+		// The normal-mode switch is the one handling R (Denote rename)
+		if slices.Contains(caseKeys(sw), "R") {
+			normal = sw
+		}
+		return true
+	})
+	if normal == nil {
+		t.Fatal("normal-mode key switch not found in main.go")
+	}
+
+	for _, key := range caseKeys(normal) {
+		if !slices.Contains(builtinKeys, key) {
+			t.Errorf("key %q is handled in normal mode but missing from builtinKeys", key)
+		}
+	}
+}
+
+// switchesOnKey reports whether sw is a switch on msg.String()
+func switchesOnKey(sw *ast.SwitchStmt) bool {
+	call, ok := sw.Tag.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "String" {
+		return false
+	}
+	recv, ok := sel.X.(*ast.Ident)
+	return ok && recv.Name == "msg"
+}
+
+// caseKeys returns the string literals sw's cases match
+func caseKeys(sw *ast.SwitchStmt) []string {
+	var keys []string
+	for _, stmt := range sw.Body.List {
+		for _, expr := range stmt.(*ast.CaseClause).List {
+			if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if key, err := strconv.Unquote(lit.Value); err == nil {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	return keys
+}
+
+func TestPeriodicKeyError(t *testing.T) {
+	config := Config{Periodic: map[string]PeriodicConfig{"monthly": {Key: "x"}}}
+	tests := []struct {
+		name, key string
+		ok        bool
+	}{
+		{"weekly", "t", false},    // open tasks filter
+		{"weekly", "e", false},    // edit
+		{"weekly", "x", false},    // monthly has it
+		{"weekly", "M", true},     // monthly moved off it
+		{"daily", "d", true},      // its own default
+		{"quarterly", "d", false}, // daily's
+		{"quarterly", "Z", true},
+	}
+	for _, tt := range tests {
+		if err := periodicKeyError(config, tt.name, tt.key); (err == nil) != tt.ok {
+			t.Errorf("periodicKeyError(%s, %q) = %v", tt.name, tt.key, err)
+		}
+	}
+}
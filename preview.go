@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/notes-tui/internal/ui"
+)
+
+// splitPreviewMsg carries a rendered note for the split layout's preview pane
+type splitPreviewMsg struct {
+	gen     int
+	file    string
+	content string
+}
+
+// previewPosition is a note shown earlier in the preview, for going back
+type previewPosition struct {
+	file   string
+	scroll int
+	link   int
+}
+
+// previewOptions returns the markdown options for rendering file in the
+// preview popover, flagging links that don't resolve to a note
+func (m *model) previewOptions(file string, activeLink int) ui.MarkdownOptions {
+	idx := m.index
+	return ui.MarkdownOptions{
+		Width:      (m.width * 80 / 100) - 6,
+		Theme:      ui.GetTheme(m.config.Theme),
+		ActiveLink: activeLink,
+		IsBroken: func(link ui.PreviewLink) bool {
+			_, ok := idx.ResolveLink(file, previewNoteLink(link))
+			return !ok
+		},
+		NoteTarget: relativeNotePath,
+	}
+}
+
+// previewNoteLink converts a rendered preview link to an index link
+func previewNoteLink(link ui.PreviewLink) noteLink {
+	kind := linkWiki
+	switch link.Kind {
+	case ui.LinkDenote:
+		kind = linkDenote
+	case ui.LinkMarkdown:
+		kind = linkMarkdown
+	}
+	return noteLink{Kind: kind, Target: link.Target}
+}
+
+// previewContentHeight is the number of content lines the popover shows
+func (m *model) previewContentHeight() int {
+	return (m.height * 80 / 100) - 8
+}
+
+// rerenderPreview redraws the loaded note, e.g. after the active link moved
+func (m *model) rerenderPreview() {
+	m.previewContent, m.previewLinks = ui.RenderMarkdown(m.previewRaw, m.previewOptions(m.previewFile, m.previewLink))
+}
+
+// cyclePreviewLink moves the active link forward or back, wrapping around,
+// and scrolls it into view
+func (m *model) cyclePreviewLink(step int) {
+	if len(m.previewLinks) == 0 {
+		return
+	}
+	if m.previewLink < 0 {
+		if step > 0 {
+			m.previewLink = 0
+		} else {
+			m.previewLink = len(m.previewLinks) - 1
+		}
+	} else {
+		m.previewLink = (m.previewLink + step + len(m.previewLinks)) % len(m.previewLinks)
+	}
+	m.rerenderPreview()
+
+	line := m.previewLinks[m.previewLink].Line
+	height := m.previewContentHeight()
+	if line < m.previewScroll {
+		m.previewScroll = line
+	} else if height > 0 && line >= m.previewScroll+height {
+		m.previewScroll = line - height + 1
+	}
+}
+
+// followPreviewLink opens the active link's note in the preview, remembering
+// the current note for previewBack
+func (m *model) followPreviewLink() tea.Cmd {
+	if m.previewLink < 0 || m.previewLink >= len(m.previewLinks) {
+		return nil
+	}
+	link := m.previewLinks[m.previewLink]
+	target, ok := m.index.ResolveLink(m.previewFile, previewNoteLink(link))
+	if !ok {
+		return ui.ShowError(fmt.Sprintf("Broken link: %s", link.Target))
+	}
+
+	m.previewHistory = append(m.previewHistory, previewPosition{file: m.previewFile, scroll: m.previewScroll, link: m.previewLink})
+	m.openPreview(target, 0, -1)
+	return m.loadPreviewForPopover()
+}
+
+// previewBack returns to the note shown before the last followed link
+func (m *model) previewBack() tea.Cmd {
+	if len(m.previewHistory) == 0 {
+		return nil
+	}
+	prev := m.previewHistory[len(m.previewHistory)-1]
+	m.previewHistory = m.previewHistory[:len(m.previewHistory)-1]
+	m.openPreview(prev.file, prev.scroll, prev.link)
+	return m.loadPreviewForPopover()
+}
+
+// openPreview points the preview at file; content arrives via previewLoadedMsg
+func (m *model) openPreview(file string, scroll, link int) {
+	m.previewFile = file
+	m.selected = file
+	m.previewScroll = scroll
+	m.previewLink = link
+	m.previewLinks = nil
+	m.previewQuery = ""
+	// Drop the old note so its scroll position is never applied to it
+	m.previewContent = ""
+	m.previewRaw = ""
+}
+
+// closePreview leaves the preview and forgets its history
+func (m *model) closePreview() {
+	m.previewMode = false
+	m.previewContent = ""
+	m.previewRaw = ""
+	m.previewLinks = nil
+	m.previewLink = -1
+	m.previewHistory = nil
+	m.previewScroll = 0
+}
+
+// syncSplitPreview starts loading the note under the cursor into the split
+// layout's preview pane when it differs from the one requested last. Any
+// load still in flight is cancelled, and its result dropped if it arrives.
+func (m *model) syncSplitPreview() tea.Cmd {
+	if ui.ParseSplitOrientation(m.config.SplitPane) == ui.SplitNone || m.width == 0 || m.ui == nil {
+		return nil
+	}
+
+	var file string
+	if m.cursor >= 0 && m.cursor < len(m.filtered) {
+		file = m.filtered[m.cursor]
+	}
+	if file == m.splitFile && file != "" {
+		return nil
+	}
+
+	if m.splitCancel != nil {
+		m.splitCancel()
+		m.splitCancel = nil
+	}
+	m.splitFile = file
+	m.splitGen++
+	if file == "" {
+		m.splitShown, m.splitContent = "", ""
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.splitCancel = cancel
+	gen := m.splitGen
+	opts := m.previewOptions(file, -1)
+	opts.Width = m.ui.PreviewPaneWidth()
+
+	return func() tea.Msg {
+		if ctx.Err() != nil {
+			return nil
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return splitPreviewMsg{gen: gen, file: file, content: fmt.Sprintf("Error reading file: %v", err)}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		rendered, _ := ui.RenderMarkdown(string(content), opts)
+		return splitPreviewMsg{gen: gen, file: file, content: rendered}
+	}
+}
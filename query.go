@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A query is a space-separated list of terms, all of which must match:
+//
+//	tag:work -tag:archived title:"standup" modified:<7d created:2025-06
+//	has:tasks path:projects/ period:weekly "free text"
+//
+// A leading - negates a term. Bare words and quoted phrases search names
+// (or contents, in content search mode) like the plain search prompt does,
+// and so do words like http://… whose prefix isn't one of queryFields.
+
+var (
+	relativeAgePattern = regexp.MustCompile(`^([<>]?)(\d+)([dw])$`)
+	datePrefixPattern  = regexp.MustCompile(`^\d{4}(-\d{2}(-\d{2})?)?$`)
+)
+
+// queryFields are the field names a term can start with
+var queryFields = []string{"tag", "title", "path", "has", "modified", "created", "period"}
+
+// queryToken is one term of a query before it is compiled
+type queryToken struct {
+	negate bool
+	field  string // empty for free text
+	value  string
+}
+
+// parseQuery compiles a query into filters for the filter stack. Free text
+// becomes a text filter that searches contents when content is set.
+func parseQuery(input string, content bool) ([]noteFilter, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var filters []noteFilter
+	var words []string
+	for _, tok := range tokens {
+		if tok.field == "" {
+			if tok.negate {
+				filters = append(filters, noteFilter{Kind: filterText, Value: tok.value, Content: content, Negate: true})
+			} else {
+				words = append(words, tok.value)
+			}
+			continue
+		}
+
+		f, err := compileQueryTerm(tok)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+
+	// All free text is searched together so ranking and snippets still work
+	if len(words) > 0 {
+		filters = append(filters, noteFilter{Kind: filterText, Value: strings.Join(words, " "), Content: content})
+	}
+	return filters, nil
+}
+
+// tokenizeQuery splits input into terms, honoring double quotes
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		if runes[i] == ' ' || runes[i] == '\t' {
+			i++
+			continue
+		}
+
+		var tok queryToken
+		if runes[i] == '-' && i+1 < len(runes) && runes[i+1] != ' ' {
+			tok.negate = true
+			i++
+		}
+
+		// Read the field name, if the term has one
+		start := i
+		for i < len(runes) && isFieldRune(runes[i]) {
+			i++
+		}
+		if field := strings.ToLower(string(runes[start:i])); i < len(runes) && runes[i] == ':' && slices.Contains(queryFields, field) {
+			tok.field = field
+			i++
+		} else {
+			i = start
+		}
+
+		// Read the value, quoted or bare
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated quote in query")
+			}
+			tok.value = string(runes[i+1 : end])
+			i = end + 1
+		} else {
+			start := i
+			for i < len(runes) && runes[i] != ' ' && runes[i] != '\t' {
+				i++
+			}
+			tok.value = string(runes[start:i])
+		}
+
+		if tok.field != "" && strings.TrimSpace(tok.value) == "" {
+			return nil, fmt.Errorf("%s: needs a value", tok.field)
+		}
+		if tok.value != "" {
+			tokens = append(tokens, tok)
+		}
+	}
+	return tokens, nil
+}
+
+func isFieldRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// compileQueryTerm turns a field:value term into a filter
+func compileQueryTerm(tok queryToken) (noteFilter, error) {
+	switch tok.field {
+	case "tag":
+		return noteFilter{Kind: filterTag, Value: strings.TrimPrefix(tok.value, "#"), Negate: tok.negate}, nil
+
+	case "title":
+		return noteFilter{Kind: filterTitle, Value: tok.value, Negate: tok.negate}, nil
+
+	case "path":
+		return noteFilter{Kind: filterPath, Value: tok.value, Negate: tok.negate}, nil
+
+	case "has":
+		switch strings.ToLower(tok.value) {
+		case "tasks", "task", "todo":
+			return noteFilter{Kind: filterTask, Negate: tok.negate}, nil
+		}
+		return noteFilter{}, fmt.Errorf("has:%s: expected has:tasks", tok.value)
+
+	case "modified", "created":
+		return compileDateTerm(tok)
+
+	case "period":
+		for _, period := range defaultPeriods {
+			if strings.EqualFold(tok.value, period.Name) {
+				return noteFilter{Kind: filterPeriod, Value: period.Name, Negate: tok.negate}, nil
+			}
+		}
+		return noteFilter{}, fmt.Errorf("period:%s: expected daily, weekly, monthly or quarterly", tok.value)
+	}
+
+	return noteFilter{}, fmt.Errorf("unknown field %q (try tag, title, path, has, modified, created or period)", tok.field)
+}
+
+// compileDateTerm handles modified: and created:, which take either a
+// relative age (<7d, >2w, 7d) or a date prefix (2025, 2025-06, 2025-06-14)
+func compileDateTerm(tok queryToken) (noteFilter, error) {
+	kind := filterCreated
+	if tok.field == "modified" {
+		kind = filterOld
+	}
+
+	if match := relativeAgePattern.FindStringSubmatch(tok.value); match != nil {
+		days, _ := strconv.Atoi(match[2])
+		if match[3] == "w" {
+			days *= 7
+		}
+		if days <= 0 {
+			return noteFilter{}, fmt.Errorf("%s:%s: age must be at least one day", tok.field, tok.value)
+		}
+		// "older than" is the negation of "within"
+		negate := tok.negate != (match[1] == ">")
+		return noteFilter{Kind: kind, Days: days, Negate: negate}, nil
+	}
+
+	if datePrefixPattern.MatchString(tok.value) {
+		if kind == filterOld {
+			kind = filterModified
+		}
+		return noteFilter{Kind: kind, Value: tok.value, Negate: tok.negate}, nil
+	}
+
+	return noteFilter{}, fmt.Errorf("%s:%s: expected an age like <7d or a date like 2025-06", tok.field, tok.value)
+}
+
+// Filter files whose title contains text (case-insensitive)
+func filterFilesByTitle(idx *NoteIndex, files []string, text string) []string {
+	text = strings.ToLower(text)
+	return idx.Select(files, func(entry *NoteEntry) bool {
+		title := entry.Title
+		if title == "" {
+			title = filepath.Base(entry.Path)
+		}
+		return strings.Contains(strings.ToLower(title), text)
+	})
+}
+
+// Filter files whose path below the notes directory contains text
+func filterFilesByPath(idx *NoteIndex, files []string, text string) []string {
+	text = strings.ToLower(filepath.ToSlash(text))
+	return idx.Select(files, func(entry *NoteEntry) bool {
+		rel, err := filepath.Rel(idx.root, entry.Path)
+		if err != nil {
+			rel = entry.Path
+		}
+		return strings.Contains(strings.ToLower(filepath.ToSlash(rel)), text)
+	})
+}
+
+// Filter files modified on a date matching prefix (YYYY, YYYY-MM or YYYY-MM-DD)
+func filterFilesByModifiedDate(idx *NoteIndex, files []string, prefix string) []string {
+	return idx.Select(files, func(entry *NoteEntry) bool {
+		return strings.HasPrefix(entry.ModTime.Format("2006-01-02"), prefix)
+	})
+}
+
+// Filter files created on a date matching prefix, or within the last days
+// when prefix is empty
+func filterFilesByCreated(idx *NoteIndex, files []string, prefix string, days int) []string {
+	cutoff := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	return idx.Select(files, func(entry *NoteEntry) bool {
+		created := noteCreatedDate(entry)
+		if created == "" {
+			return false
+		}
+		if prefix != "" {
+			return strings.HasPrefix(created, prefix)
+		}
+		return created >= cutoff
+	})
+}
+
+// noteCreatedDate returns a note's creation date as YYYY-MM-DD, taken from
+// its Denote identifier, its frontmatter date, or a date-prefixed filename
+func noteCreatedDate(entry *NoteEntry) string {
+	if id := entry.Identifier; len(id) >= 8 {
+		return id[0:4] + "-" + id[4:6] + "-" + id[6:8]
+	}
+	if date := entry.Frontmatter["date"]; len(date) >= 10 && datePrefixPattern.MatchString(date[:10]) {
+		return date[:10]
+	}
+	if name := filepath.Base(entry.Path); len(name) >= 10 && datePrefixPattern.MatchString(name[:10]) {
+		return name[:10]
+	}
+	return ""
+}
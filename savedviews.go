@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SavedView is a named set of filters defined in config.toml:
+//
+//	[[views]]
+//	name = "Inbox"
+//	tags = ["inbox"]
+//	exclude_tags = ["archived"]
+//	days = 7
+type SavedView struct {
+	Name        string   `toml:"name"`
+	Tags        []string `toml:"tags"`         // notes must carry all of these
+	ExcludeTags []string `toml:"exclude_tags"` // notes must carry none of these
+	Search      string   `toml:"search"`       // filename search, query syntax allowed
+	Days        int      `toml:"days"`         // modified within the last N days
+	TasksOnly   bool     `toml:"tasks_only"`   // only notes with open tasks
+	Sort        string   `toml:"sort"`         // "date", "modified", "title" or "denote"
+}
+
+// Filters compiles the view into filter stack entries
+func (v SavedView) Filters() ([]noteFilter, error) {
+	var filters []noteFilter
+	for _, tag := range v.Tags {
+		if tag = strings.TrimPrefix(strings.TrimSpace(tag), "#"); tag != "" {
+			filters = append(filters, noteFilter{Kind: filterTag, Value: tag})
+		}
+	}
+	for _, tag := range v.ExcludeTags {
+		if tag = strings.TrimPrefix(strings.TrimSpace(tag), "#"); tag != "" {
+			filters = append(filters, noteFilter{Kind: filterTag, Value: tag, Negate: true})
+		}
+	}
+	if v.Days > 0 {
+		filters = append(filters, noteFilter{Kind: filterOld, Days: v.Days})
+	}
+	if v.TasksOnly {
+		filters = append(filters, noteFilter{Kind: filterTask})
+	}
+	if strings.TrimSpace(v.Search) != "" {
+		query, err := parseQuery(v.Search, false)
+		if err != nil {
+			return nil, fmt.Errorf("view %q: %v", v.Name, err)
+		}
+		filters = append(filters, query...)
+	}
+	return filters, nil
+}
+
+// Summary describes the view's filters for the picker
+func (v SavedView) Summary() string {
+	filters, err := v.Filters()
+	if err != nil {
+		return err.Error()
+	}
+	var parts []string
+	for _, f := range filters {
+		parts = append(parts, f.Label())
+	}
+	if v.Sort != "" {
+		parts = append(parts, "Sort: "+v.Sort)
+	}
+	if len(parts) == 0 {
+		return "All notes"
+	}
+	return strings.Join(parts, "  ")
+}
+
+// findView looks up a configured view by name (case-insensitive)
+func findView(config Config, name string) (SavedView, bool) {
+	for _, view := range config.Views {
+		if strings.EqualFold(view.Name, name) {
+			return view, true
+		}
+	}
+	return SavedView{}, false
+}
+
+// applyView replaces the filter stack with the view's filters and sort
+func (m *model) applyView(view SavedView) error {
+	filters, err := view.Filters()
+	if err != nil {
+		return err
+	}
+
+	switch view.Sort {
+	case "":
+	case "date", "modified", "title", "denote":
+		m.currentSort = view.Sort
+		m.files = m.applySorting(m.files)
+	default:
+		return fmt.Errorf("view %q: unknown sort %q", view.Name, view.Sort)
+	}
+
+	m.filters = filters
+	m.activeView = view.Name
+	m.applyActiveFilters()
+	m.cursor = 0
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pdxmph/notes-tui/internal/frontmatter"
+)
+
+// maxSnippetsPerNote caps how many matching lines are shown under a result
+const maxSnippetsPerNote = 3
+
+// contentMatch is one line of a note that matched a content search
+type contentMatch struct {
+	Line int    // 1-based line number in the file
+	Text string // the matching line, trimmed
+}
+
+// searchContent finds notes whose body contains query (case-insensitive) and
+// returns them in the order given along with the first few matching lines.
+// The index doesn't keep note content, so the notes are read from disk in
+// parallel.
+func searchContent(idx *NoteIndex, files []string, query string) ([]string, map[string][]contentMatch) {
+	matches := make(map[string][]contentMatch)
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return files, matches
+	}
+
+	notes := idx.Select(files, func(*NoteEntry) bool { return true })
+	found := make([][]contentMatch, len(notes))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if content, err := os.ReadFile(notes[i]); err == nil {
+					found[i] = matchContent(string(content), query)
+				}
+			}
+		}()
+	}
+	for i := range notes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var result []string
+	for i, path := range notes {
+		if found[i] != nil {
+			result = append(result, path)
+			matches[path] = found[i]
+		}
+	}
+	return result, matches
+}
+
+// matchContent returns the first few lines of a note's body containing
+// query, which must be lowercase, or nil when there are none
+func matchContent(content, query string) []contentMatch {
+	// Frontmatter keys would match every note, so only the body counts
+	_, _, body, start := frontmatter.Split(content)
+
+	// Cheap whole-body check before splitting into lines
+	if !strings.Contains(strings.ToLower(body), query) {
+		return nil
+	}
+
+	var found []contentMatch
+	for i, line := range strings.Split(body, "\n") {
+		if strings.Contains(strings.ToLower(line), query) {
+			found = append(found, contentMatch{Line: start + i + 1, Text: strings.TrimSpace(line)})
+			if len(found) == maxSnippetsPerNote {
+				break
+			}
+		}
+	}
+	return found
+}
+
+// firstMatchingLine returns the index of the first line containing query
+// (case-insensitive), or -1. Lines are compared without ANSI styling.
+func firstMatchingLine(lines []string, query string) int {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return -1
+	}
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), query) {
+			return i
+		}
+	}
+	return -1
+}
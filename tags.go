@@ -0,0 +1,233 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pdxmph/notes-tui/internal/frontmatter"
+)
+
+// extractTags collects every tag a note carries: frontmatter tags, Denote
+// filename keywords and inline #tags in the body. Duplicates are dropped
+// but the original spelling of the first occurrence is kept.
+func extractTags(path, content string, frontmatterTags []string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		key := normalizeTag(tag)
+		if key != "" && !seen[key] {
+			seen[key] = true
+			tags = append(tags, strings.TrimPrefix(tag, "#"))
+		}
+	}
+
+	for _, tag := range frontmatterTags {
+		add(tag)
+	}
+	for _, tag := range extractDenoteTags(path) {
+		add(tag)
+	}
+	for _, tag := range extractInlineTags(content) {
+		add(tag)
+	}
+	return tags
+}
+
+// extractInlineTags finds #tag tokens in note content, ignoring frontmatter,
+// fenced code blocks, inline code spans and markdown headings
+func extractInlineTags(content string) []string {
+	var tags []string
+	lines := strings.Split(frontmatter.Body(content), "\n")
+
+	var fence string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Track fenced code blocks (``` or ~~~)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			fence = "```"
+			continue
+		}
+		if strings.HasPrefix(trimmed, "~~~") {
+			fence = "~~~"
+			continue
+		}
+
+		tags = append(tags, scanLineTags(stripInlineCode(line))...)
+	}
+	return tags
+}
+
+// scanLineTags returns the #tags found in a single line of prose
+func scanLineTags(line string) []string {
+	var tags []string
+	runes := []rune(line)
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '#' {
+			continue
+		}
+		// A tag must start a word: "page#anchor" and "##" are not tags
+		if i > 0 && !isTagBoundary(runes[i-1]) {
+			continue
+		}
+		// Nor is the anchor a link points at: [see](#section-2)
+		if i > 1 && runes[i-1] == '(' && runes[i-2] == ']' {
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && isTagRune(runes[j]) {
+			j++
+		}
+		tag := strings.TrimRight(string(runes[i+1:j]), ".")
+		if tag != "" && !isNumeric(tag) {
+			tags = append(tags, tag)
+		}
+		i = j - 1
+	}
+	return tags
+}
+
+// stripInlineCode blanks out `code spans` so their contents aren't scanned
+func stripInlineCode(line string) string {
+	if !strings.Contains(line, "`") {
+		return line
+	}
+	var b strings.Builder
+	inCode := false
+	for _, r := range line {
+		if r == '`' {
+			inCode = !inCode
+			b.WriteRune(' ')
+			continue
+		}
+		if inCode {
+			b.WriteRune(' ')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isTagBoundary reports whether r may precede the # that opens a tag
+func isTagBoundary(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("([{,;", r)
+}
+
+// isTagRune reports whether r may appear inside a tag
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-/@.+", r)
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTag returns the comparison form of a tag (no leading #, lowercase)
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// hasTag reports whether an entry carries tag (case-insensitive)
+func (e *NoteEntry) hasTag(tag string) bool {
+	want := normalizeTag(tag)
+	for _, t := range e.Tags {
+		if normalizeTag(t) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Search for files carrying a specific tag using the note index. When
+// use_ripgrep is enabled and rg is installed, ripgrep narrows the candidate
+// set with a fixed-string match first; the parsed tags always decide.
+func searchTag(idx *NoteIndex, files []string, tag string) []string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if tag == "" {
+		return nil
+	}
+
+	if idx.config.UseRipgrep {
+		if candidates, ok := ripgrepCandidates(idx.root, tag); ok {
+			return idx.Select(files, func(entry *NoteEntry) bool {
+				// Denote keywords live in the filename, which rg doesn't search
+				mentioned := candidates[entry.Path] ||
+					strings.Contains(strings.ToLower(filepath.Base(entry.Path)), strings.ToLower(tag))
+				return mentioned && entry.hasTag(tag)
+			})
+		}
+	}
+
+	return idx.Select(files, func(entry *NoteEntry) bool {
+		return entry.hasTag(tag)
+	})
+}
+
+// ripgrepCandidates lists files whose content mentions text. The
+// search is a literal, case-insensitive match so user input is never
+// interpreted as a regex. ok is false when rg is unavailable or fails.
+func ripgrepCandidates(dir, text string) (map[string]bool, bool) {
+	if _, err := exec.LookPath("rg"); err != nil {
+		return nil, false
+	}
+
+	// Search every kind of file a note can be; files that aren't notes
+	// never make it into the list being filtered
+	args := []string{"-l", "-i", "-F"}
+	for _, ext := range append(slices.Clone(markdownExtensions), denoteExtensions...) {
+		args = append(args, "--iglob", "*"+ext)
+	}
+	cmd := exec.Command("rg", append(args, "--", text, dir)...)
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit code 1 just means nothing matched
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return map[string]bool{}, true
+		}
+		return nil, false
+	}
+
+	candidates := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			candidates[line] = true
+		}
+	}
+	return candidates, true
+}
+
+// allTags returns every tag in the index once, in the spelling first seen,
+// sorted case-insensitively
+func allTags(idx *NoteIndex) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, entry := range idx.Entries() {
+		for _, tag := range entry.Tags {
+			if key := normalizeTag(tag); key != "" && !seen[key] {
+				seen[key] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return normalizeTag(tags[i]) < normalizeTag(tags[j])
+	})
+	return tags
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// taskLinePattern matches a list item with a checkbox, capturing what
+	// comes before the box, its state, and the task text
+	taskLinePattern = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+\[)([ xX])\]\s+(.*)$`)
+
+	// Inline task metadata: due:2025-07-01 or 📅 2025-07-01, @today, and a
+	// priority as !high, !medium, !low or one of ⏫ 🔺 🔼 🔽
+	taskDuePattern      = regexp.MustCompile(`(?:^|\s)(?:due:|📅\s*)(\d{4}-\d{2}-\d{2})\b`)
+	taskTodayPattern    = regexp.MustCompile(`(?:^|\s)@today\b`)
+	taskPriorityPattern = regexp.MustCompile(`(?:^|\s)(?:!(high|medium|med|low)\b|(⏫|🔺|🔼|🔽)\x{FE0F}?)`)
+)
+
+// Task priorities, lowest first
+const (
+	priorityNone = iota
+	priorityLow
+	priorityMedium
+	priorityHigh
+)
+
+// noteTask is a checkbox line in a note
+type noteTask struct {
+	Line     int    // 1-based line number
+	Text     string // text after the checkbox, as written
+	Summary  string // text with the metadata taken out
+	Done     bool
+	Due      string // due date as YYYY-MM-DD, if any
+	Today    bool   // marked @today
+	Priority int
+}
+
+// taskGroup is an agenda section
+type taskGroup int
+
+const (
+	groupOverdue taskGroup = iota
+	groupToday
+	groupThisWeek
+	groupSomeday
+	groupDone
+)
+
+var taskGroupNames = [...]string{"Overdue", "Today", "This week", "Someday", "Done"}
+
+// String returns the section heading
+func (g taskGroup) String() string {
+	return taskGroupNames[g]
+}
+
+// taskItem is a task in the task view, with the note it lives in
+type taskItem struct {
+	Path string
+	noteTask
+}
+
+// extractTasks finds every checkbox line in content, skipping frontmatter
+// and fenced code blocks
+func extractTasks(content string) []noteTask {
+	var tasks []noteTask
+	eachBodyLine(content, func(num int, line string) {
+		if match := taskLinePattern.FindStringSubmatch(line); match != nil {
+			task := noteTask{
+				Line: num,
+				Text: strings.TrimSpace(match[3]),
+				Done: match[2] != " ",
+			}
+			parseTaskMetadata(&task)
+			tasks = append(tasks, task)
+		}
+	})
+	return tasks
+}
+
+// parseTaskMetadata fills in a task's due date, @today mark and priority
+// from its text, leaving the rest as its summary
+func parseTaskMetadata(task *noteTask) {
+	summary := task.Text
+
+	if match := taskDuePattern.FindStringSubmatch(summary); match != nil {
+		if _, err := time.Parse("2006-01-02", match[1]); err == nil {
+			task.Due = match[1]
+			summary = taskDuePattern.ReplaceAllString(summary, "")
+		}
+	}
+	if taskTodayPattern.MatchString(summary) {
+		task.Today = true
+		summary = taskTodayPattern.ReplaceAllString(summary, "")
+	}
+	if match := taskPriorityPattern.FindStringSubmatch(summary); match != nil {
+		switch match[1] + match[2] {
+		case "high", "⏫", "🔺":
+			task.Priority = priorityHigh
+		case "medium", "med", "🔼":
+			task.Priority = priorityMedium
+		case "low", "🔽":
+			task.Priority = priorityLow
+		}
+		summary = taskPriorityPattern.ReplaceAllString(summary, "")
+	}
+
+	task.Summary = strings.Join(strings.Fields(summary), " ")
+	if task.Summary == "" {
+		task.Summary = task.Text
+	}
+}
+
+// group places a task in the agenda relative to today (YYYY-MM-DD). This
+// week covers the seven days after today.
+func (t noteTask) group(today string) taskGroup {
+	day, _ := time.Parse("2006-01-02", today)
+	weekEnd := day.AddDate(0, 0, 7).Format("2006-01-02")
+	switch {
+	case t.Done:
+		return groupDone
+	case t.Due != "" && t.Due < today:
+		return groupOverdue
+	case t.Today || t.Due == today:
+		return groupToday
+	case t.Due != "" && t.Due <= weekEnd:
+		return groupThisWeek
+	}
+	return groupSomeday
+}
+
+// metadataLabel describes a task's due date and priority for the task view
+func (t noteTask) metadataLabel() string {
+	var parts []string
+	if t.Today {
+		parts = append(parts, "@today")
+	}
+	if t.Due != "" {
+		parts = append(parts, "due "+t.Due)
+	}
+	switch t.Priority {
+	case priorityHigh:
+		parts = append(parts, "!high")
+	case priorityMedium:
+		parts = append(parts, "!medium")
+	case priorityLow:
+		parts = append(parts, "!low")
+	}
+	return strings.Join(parts, "  ")
+}
+
+// sortTasks orders tasks by agenda group when grouped, then by the chosen
+// key: "due" (soonest first, undated last) or "priority" (highest first).
+// Ties keep file list order.
+func sortTasks(tasks []taskItem, grouped bool, by string, today string) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if grouped {
+			if ga, gb := a.group(today), b.group(today); ga != gb {
+				return ga < gb
+			}
+		}
+		switch by {
+		case "due":
+			if a.Due != b.Due {
+				return b.Due == "" || a.Due != "" && a.Due < b.Due
+			}
+			return a.Priority > b.Priority
+		case "priority":
+			if a.Priority != b.Priority {
+				return a.Priority > b.Priority
+			}
+			return a.Due != b.Due && (b.Due == "" || a.Due != "" && a.Due < b.Due)
+		}
+		return false
+	})
+}
+
+// refreshTasks rebuilds the task view from the index, following the order
+// of the file list and the view's tag filter, grouped and sorted as chosen
+func (m *model) refreshTasks() {
+	var tasks []taskItem
+	for _, path := range m.files {
+		entry, ok := m.index.Get(path)
+		if !ok || len(entry.Tasks) == 0 {
+			continue
+		}
+		if m.taskTag != "" && !entry.hasTag(m.taskTag) {
+			continue
+		}
+		for _, task := range entry.Tasks {
+			if task.Done && !m.taskShowDone {
+				continue
+			}
+			tasks = append(tasks, taskItem{Path: path, noteTask: task})
+		}
+	}
+	sortTasks(tasks, m.taskAgenda, m.taskSort, time.Now().Format("2006-01-02"))
+	m.tasks = tasks
+
+	if m.taskCursor >= len(m.tasks) {
+		m.taskCursor = len(m.tasks) - 1
+	}
+	if m.taskCursor < 0 {
+		m.taskCursor = 0
+	}
+}
+
+// toggleTask flips a task between [ ] and [x] by rewriting its line in the
+// note, refusing if the line no longer holds the task
+func toggleTask(idx *NoteIndex, task taskItem) error {
+	info, err := os.Stat(task.Path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(task.Path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if task.Line < 1 || task.Line > len(lines) {
+		return fmt.Errorf("line %d is no longer in the note", task.Line)
+	}
+	line := lines[task.Line-1]
+	match := taskLinePattern.FindStringSubmatchIndex(strings.TrimSuffix(line, "\r"))
+	if match == nil || strings.TrimSpace(line[match[6]:match[7]]) != task.Text {
+		return fmt.Errorf("task on line %d has changed; reload and try again", task.Line)
+	}
+
+	box := "x"
+	if line[match[4]:match[5]] != " " {
+		box = " "
+	}
+	lines[task.Line-1] = line[:match[4]] + box + line[match[5]:]
+
+	if err := os.WriteFile(task.Path, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return idx.Update(task.Path)
+}
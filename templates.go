@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pdxmph/notes-tui/internal/frontmatter"
+	"github.com/pdxmph/notes-tui/internal/ui"
+)
+
+// noteTemplate is a text/template file in the templates directory
+type noteTemplate struct {
+	Name string // file name without .md
+	Path string
+}
+
+// templateData is what templates can refer to, e.g. {{.Title}}
+type templateData struct {
+	Title      string
+	Date       string // YYYY-MM-DD
+	Time       string // HH:MM
+	Identifier string // Denote identifier, YYYYMMDDTHHMMSS
+	Tags       templateTags
+	Yesterday  string // YYYY-MM-DD
+	Carryover  string // daily notes only: tasks carried over from the last one
+
+	created time.Time // day the note is for
+}
+
+// templateTags prints as a comma-separated list but can still be ranged over
+type templateTags []string
+
+func (t templateTags) String() string {
+	return strings.Join(t, ", ")
+}
+
+// templatesDir returns the configured templates directory, or templates/
+// next to the config file
+func templatesDir(config Config) string {
+	if config.TemplatesDirectory != "" {
+		return expandPath(config.TemplatesDirectory)
+	}
+	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
+		dir := filepath.Join(xdgConfig, "notes-tui", "templates")
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config", "notes-tui", "templates")
+}
+
+// loadTemplates lists the templates in dir by name
+func loadTemplates(dir string) []noteTemplate {
+	if dir == "" {
+		return nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.md"))
+	var templates []noteTemplate
+	for _, path := range matches {
+		templates = append(templates, noteTemplate{
+			Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+			Path: path,
+		})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return strings.ToLower(templates[i].Name) < strings.ToLower(templates[j].Name)
+	})
+	return templates
+}
+
+// findTemplate looks up a template by name, with or without .md
+func findTemplate(config Config, name string) (noteTemplate, bool) {
+	name = strings.TrimSuffix(name, ".md")
+	for _, tmpl := range loadTemplates(templatesDir(config)) {
+		if strings.EqualFold(tmpl.Name, name) {
+			return tmpl, true
+		}
+	}
+	return noteTemplate{}, false
+}
+
+// newTemplateData fills in the template variables for a note for the given
+// day, created now
+func newTemplateData(title, identifier string, tags []string, date time.Time) templateData {
+	now := time.Now()
+	if identifier == "" {
+		identifier = now.Format("20060102T150405")
+	}
+	return templateData{
+		Title:      title,
+		Date:       date.Format("2006-01-02"),
+		Time:       now.Format("15:04"),
+		Identifier: identifier,
+		Tags:       templateTags(tags),
+		Yesterday:  date.AddDate(0, 0, -1).Format("2006-01-02"),
+		created:    date,
+	}
+}
+
+// render executes the template. Output that starts with its own frontmatter
+// is used as is; anything else goes below the usual generated header.
+func (t noteTemplate) render(data templateData, config Config) (string, error) {
+	raw, err := os.ReadFile(t.Path)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("template %s: %v", t.Name, err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("template %s: %v", t.Name, err)
+	}
+	body := b.String()
+
+	// Carried-over tasks go under the Tasks heading unless placed explicitly
+	if data.Carryover != "" && !strings.Contains(string(raw), ".Carryover") {
+		body = insertUnderHeading(body, "## Tasks", data.Carryover)
+	}
+
+	if frontmatter.Has(body) {
+		return body, nil
+	}
+	return generateNoteContent(data.Title, config, data.Identifier, []string(data.Tags), data.created) + body, nil
+}
+
+// insertUnderHeading adds text after the first line equal to heading, or at
+// the end when there is no such line
+func insertUnderHeading(body, heading, text string) string {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == heading {
+			rest := strings.Join(lines[i+1:], "\n")
+			return strings.Join(lines[:i+1], "\n") + "\n\n" + text + strings.TrimLeft(rest, "\n")
+		}
+	}
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	return body + "\n" + text
+}
+
+// finishCreate creates a note once its title and tags are known, first
+// asking for a template when there are any
+func (m *model) finishCreate(title string, tags []string) tea.Cmd {
+	templates := loadTemplates(templatesDir(m.config))
+	if len(templates) == 0 {
+		return m.createNote(title, tags, nil)
+	}
+	m.templateMode = true
+	m.templates = templates
+	m.templateCursor = 0
+	m.pendingTitle = title
+	m.pendingTags = tags
+	return nil
+}
+
+// createNote writes a new note, from a template if one is given, and opens
+// it in the editor
+func (m *model) createNote(title string, tags []string, tmpl *noteTemplate) tea.Cmd {
+	var filename, identifier string
+	if m.config.DenoteFilenames {
+		filename, identifier = generateDenoteName(title, tags, time.Now(), m.config)
+	} else {
+		filename = titleToFilename(title)
+	}
+	fullPath := filepath.Join(m.cwd, filename)
+
+	content := generateNoteContent(title, m.config, identifier, tags, time.Now())
+	if tmpl != nil {
+		rendered, err := tmpl.render(newTemplateData(title, identifier, tags, time.Now()), m.config)
+		if err != nil {
+			return ui.ShowError(err.Error())
+		}
+		content = rendered
+	}
+
+	if err := writeNewFile(fullPath, []byte(content)); err != nil {
+		return ui.ShowError(fmt.Sprintf("Error creating note: %v", err))
+	}
+	op := newOperation("creation of " + filename)
+	op.created(fullPath)
+	saved := m.record(op, nil)
+	m.selected = fullPath
+	// Add the new file to the index and refresh the list
+	m.index.Update(fullPath)
+	m.refreshFiles()
+	m.clearFilters()
+	// Find and select the new file
+	for i, f := range m.filtered {
+		if f == fullPath {
+			m.cursor = i
+			break
+		}
+	}
+	return tea.Batch(saved, tea.ExecProcess(m.openInEditor(), func(err error) tea.Msg {
+		return clearSelectedMsg{}
+	}))
+}
+
+// writeNewFile writes a note that must not exist yet, so a new note whose
+// name collides with an old one never replaces it
+func writeNewFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return fmt.Errorf("%s already exists", filepath.Base(path))
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// watchDebounce is how long the watcher waits for a burst of events to settle
+	watchDebounce = 250 * time.Millisecond
+	// watchPollInterval is how often the polling fallback rescans the
+	// directories inotify couldn't watch
+	watchPollInterval = 2 * time.Second
+)
+
+// filesChangedMsg carries a debounced batch of paths that changed on disk
+type filesChangedMsg struct {
+	paths []string
+}
+
+// noteWatcher reports external changes under the notes directory. It uses
+// inotify (via fsnotify) where it can and polls the directories it can't
+// watch, e.g. once past the inotify watch limit.
+type noteWatcher struct {
+	root    string
+	fsw     *fsnotify.Watcher
+	changes chan []string
+	done    chan struct{}
+	once    sync.Once
+
+	mu        sync.Mutex
+	unwatched map[string]bool // directories left to the poller
+}
+
+// newNoteWatcher starts watching dir and returns the running watcher
+func newNoteWatcher(dir string) *noteWatcher {
+	w := &noteWatcher{
+		root:      dir,
+		changes:   make(chan []string),
+		done:      make(chan struct{}),
+		unwatched: make(map[string]bool),
+	}
+
+	// Without inotify at all every directory ends up polled
+	if fsw, err := fsnotify.NewWatcher(); err == nil {
+		w.fsw = fsw
+	}
+	w.addTree(dir)
+
+	if w.fsw != nil {
+		go w.watchLoop()
+	}
+	go w.pollLoop()
+	return w
+}
+
+// Polled returns how many directories are polled rather than watched
+func (w *noteWatcher) Polled() int {
+	if w == nil {
+		return 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.unwatched)
+}
+
+// Wait returns a command that delivers the next batch of changes
+func (w *noteWatcher) Wait() tea.Cmd {
+	if w == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		select {
+		case paths := <-w.changes:
+			return filesChangedMsg{paths: paths}
+		case <-w.done:
+			return nil
+		}
+	}
+}
+
+// Close stops the watcher
+func (w *noteWatcher) Close() {
+	if w == nil {
+		return
+	}
+	w.once.Do(func() {
+		close(w.done)
+		if w.fsw != nil {
+			w.fsw.Close()
+		}
+	})
+}
+
+// addTree registers a watch on dir and every non-hidden directory below it.
+// Directories that can't be watched are handed to the poller instead.
+func (w *noteWatcher) addTree(dir string) {
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(info.Name(), ".") && path != dir {
+			return filepath.SkipDir
+		}
+		if w.fsw == nil || w.fsw.Add(path) != nil {
+			w.mu.Lock()
+			w.unwatched[path] = true
+			w.mu.Unlock()
+		}
+		return nil
+	})
+}
+
+// watchLoop collects inotify events and emits them once they settle
+func (w *noteWatcher) watchLoop() {
+	pending := make(map[string]bool)
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			timer.Stop()
+			return
+
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if w.isHidden(event.Name) {
+				continue
+			}
+			// Newly created directories need their own watches
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.addTree(event.Name)
+				}
+			}
+			pending[event.Name] = true
+			timer.Reset(watchDebounce)
+
+		case _, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			pending = make(map[string]bool)
+			if !w.send(paths) {
+				return
+			}
+		}
+	}
+}
+
+// pollLoop rescans the unwatched directories periodically and emits
+// whatever changed
+func (w *noteWatcher) pollLoop() {
+	snapshot := w.scan()
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			current := w.scan()
+			var paths []string
+			for path, modTime := range current {
+				if previous, ok := snapshot[path]; !ok || !previous.Equal(modTime) {
+					paths = append(paths, path)
+				}
+			}
+			for path := range snapshot {
+				if _, ok := current[path]; !ok {
+					paths = append(paths, path)
+				}
+			}
+			snapshot = current
+			// New directories need watching, or polling, like the rest
+			for _, path := range paths {
+				if info, err := os.Stat(path); err == nil && info.IsDir() {
+					w.addTree(path)
+				}
+			}
+			if len(paths) > 0 && !w.send(paths) {
+				return
+			}
+		}
+	}
+}
+
+// scan records the modification time of every note directly inside the
+// unwatched directories for the poller. Subdirectories are recorded with a
+// zero time, so only their appearing or going away counts as a change.
+func (w *noteWatcher) scan() map[string]time.Time {
+	w.mu.Lock()
+	dirs := make([]string, 0, len(w.unwatched))
+	for dir := range w.unwatched {
+		dirs = append(dirs, dir)
+	}
+	w.mu.Unlock()
+
+	result := make(map[string]time.Time)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			w.mu.Lock()
+			delete(w.unwatched, dir)
+			w.mu.Unlock()
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			if entry.IsDir() {
+				result[path] = time.Time{}
+			} else if isNoteFile(path) {
+				if info, err := entry.Info(); err == nil {
+					result[path] = info.ModTime()
+				}
+			}
+		}
+	}
+	return result
+}
+
+// send hands a batch to the UI, giving up if the watcher is closed
+func (w *noteWatcher) send(paths []string) bool {
+	select {
+	case w.changes <- paths:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+// isHidden reports whether a path lies inside a hidden file or directory
+func (w *noteWatcher) isHidden(path string) bool {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return false
+	}
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if strings.HasPrefix(part, ".") && part != "." && part != ".." {
+			return true
+		}
+	}
+	return false
+}